@@ -0,0 +1,206 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cbor implements the small subset of CBOR (RFC 8949) needed to
+// exchange identifiers as text strings, optionally enclosed in tags, so that
+// the public packages can implement the MarshalCBOR and UnmarshalCBOR methods
+// expected by CBOR libraries without depending on one.
+package cbor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// Tags registered with IANA for the identifiers of this module.
+const (
+	TagURI = 32 // Tag of a URI (RFC 8949, Section 3.4.5.3).
+)
+
+// Errors that can occur when decoding a data item.
+var (
+	ErrMalformed      = errors.New("cbor: malformed data item")
+	ErrUnexpectedType = errors.New("cbor: the data item is not a text string")
+)
+
+// Major types of the initial byte of a data item (RFC 8949, Section 3.1).
+const (
+	majorText = 3
+	majorTag  = 6
+)
+
+// Layout of the initial byte of a data item (RFC 8949, Section 3): the major
+// type in the high-order 3 bits, and the additional information in the
+// low-order 5 bits.
+const (
+	majorShift = 5
+	infoMask   = 0x1F
+	byteBits   = 8
+)
+
+// Additional information values of the initial byte (RFC 8949, Section 3).
+const (
+	maxInlineArgument = 23   // Largest argument stored in the initial byte itself.
+	argument1Byte     = 24   // The argument follows in 1 byte.
+	argument2Bytes    = 25   // The argument follows in 2 bytes.
+	argument4Bytes    = 26   // The argument follows in 4 bytes.
+	argument8Bytes    = 27   // The argument follows in 8 bytes.
+	indefiniteLength  = 31   // The item is an indefinite-length string.
+	breakByte         = 0xFF // Terminates an indefinite-length string.
+)
+
+// AppendText appends s to dst as a definite-length text string, enclosed in
+// the given tags, the outermost first, and returns the extended buffer.
+func AppendText(dst []byte, s string, tags ...uint64) []byte {
+	for _, tag := range tags {
+		dst = appendHead(dst, majorTag, tag)
+	}
+	dst = appendHead(dst, majorText, uint64(len(s)))
+	return append(dst, s...)
+}
+
+// DecodeText decodes data, which must hold exactly one text string, of
+// definite or indefinite length, possibly enclosed in tags. It returns the
+// text and the tags, the outermost first. It fails with ErrUnexpectedType if
+// the data item is not a text string, and with ErrMalformed if it is not well
+// formed or its text is not valid UTF-8.
+func DecodeText(data []byte) (string, []uint64, error) {
+	var tags []uint64
+	h, err := readHead(data)
+	for err == nil && h.major == majorTag {
+		if h.info == indefiniteLength {
+			return "", nil, fmt.Errorf("%w: a tag cannot have an indefinite length", ErrMalformed)
+		}
+		tags = append(tags, h.arg)
+		data = data[h.size:]
+		h, err = readHead(data)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if h.major != majorText {
+		return "", nil, ErrUnexpectedType
+	}
+
+	var text, rest []byte
+	if h.info == indefiniteLength {
+		text, rest, err = readChunks(data[h.size:])
+	} else {
+		text, rest, err = readBytes(data[h.size:], h.arg)
+	}
+	switch {
+	case err != nil:
+		return "", nil, err
+	case len(rest) != 0:
+		return "", nil, fmt.Errorf("%w: trailing data after the text string", ErrMalformed)
+	case !utf8.Valid(text):
+		return "", nil, fmt.Errorf("%w: the text string is not valid UTF-8", ErrMalformed)
+	}
+	return string(text), tags, nil
+}
+
+// readChunks reads the definite-length text strings that make up an
+// indefinite-length one, up to the break byte, and returns their concatenation
+// and the remaining data.
+func readChunks(data []byte) ([]byte, []byte, error) {
+	text := []byte{}
+	for {
+		if len(data) == 0 {
+			return nil, nil, fmt.Errorf("%w: missing break of an indefinite-length string", ErrMalformed)
+		}
+		if data[0] == breakByte {
+			return text, data[1:], nil
+		}
+		h, err := readHead(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if h.major != majorText || h.info == indefiniteLength {
+			return nil, nil, fmt.Errorf("%w: invalid chunk in an indefinite-length string", ErrMalformed)
+		}
+		chunk, rest, err := readBytes(data[h.size:], h.arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		text = append(text, chunk...)
+		data = rest
+	}
+}
+
+// readBytes splits the first length bytes off data.
+func readBytes(data []byte, length uint64) ([]byte, []byte, error) {
+	if length > uint64(len(data)) {
+		return nil, nil, fmt.Errorf("%w: unexpected end of data", ErrMalformed)
+	}
+	return data[:length], data[length:], nil
+}
+
+// appendHead appends the initial byte of a data item of the given major type,
+// followed by its argument in the shortest form.
+func appendHead(dst []byte, major byte, arg uint64) []byte {
+	major <<= majorShift
+	switch {
+	case arg <= maxInlineArgument:
+		return append(dst, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(dst, major|argument1Byte, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, major|argument2Bytes), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, major|argument4Bytes), uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, major|argument8Bytes), arg)
+	}
+}
+
+// head is the decoded head of a data item (RFC 8949, Section 3).
+type head struct {
+	major byte   // Major type.
+	info  byte   // Additional information, e.g., indefiniteLength.
+	arg   uint64 // Argument, unused for an indefinite-length item.
+	size  int    // Length of the head in bytes.
+}
+
+// readHead reads the head of the data item at the start of data.
+func readHead(data []byte) (head, error) {
+	if len(data) == 0 {
+		return head{}, fmt.Errorf("%w: unexpected end of data", ErrMalformed)
+	}
+	h := head{major: data[0] >> majorShift, info: data[0] & infoMask, size: 1}
+	switch {
+	case h.info <= maxInlineArgument:
+		h.arg = uint64(h.info)
+		return h, nil
+	case h.info == indefiniteLength:
+		return h, nil
+	case h.info > argument8Bytes:
+		return head{}, fmt.Errorf("%w: reserved additional information %d", ErrMalformed, h.info)
+	}
+	// The argument follows in 1, 2, 4, or 8 bytes, from argument1Byte to
+	// argument8Bytes.
+	extra := 1 << (h.info - argument1Byte)
+	if len(data) < h.size+extra {
+		return head{}, fmt.Errorf("%w: unexpected end of data", ErrMalformed)
+	}
+	for _, b := range data[h.size : h.size+extra] {
+		h.arg = h.arg<<byteBits | uint64(b)
+	}
+	h.size += extra
+	return h, nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestAppendText tests the encoding of text strings, using examples from
+// RFC 8949, Appendix A, for the shortest forms of the length.
+func TestAppendText(t *testing.T) {
+	testCases := []struct {
+		name string
+		s    string
+		tags []uint64
+		want []byte
+	}{
+		{name: "Empty", s: "", want: []byte{0x60}},
+		{name: "Inline length", s: "IETF", want: []byte{0x64, 'I', 'E', 'T', 'F'}},
+		{name: "Non-ASCII", s: "ü", want: []byte{0x62, 0xc3, 0xbc}},
+		{
+			name: "URI tag",
+			s:    "http://www.example.com",
+			tags: []uint64{TagURI},
+			want: append([]byte{0xd8, 0x20, 0x76}, "http://www.example.com"...),
+		},
+		{name: "1-byte length", s: strings.Repeat("a", 24), want: append([]byte{0x78, 24}, strings.Repeat("a", 24)...)},
+		{
+			name: "2-byte length",
+			s:    strings.Repeat("a", 256),
+			want: append([]byte{0x79, 0x01, 0x00}, strings.Repeat("a", 256)...),
+		},
+		{
+			name: "4-byte length",
+			s:    strings.Repeat("a", 65536),
+			want: append([]byte{0x7a, 0x00, 0x01, 0x00, 0x00}, strings.Repeat("a", 65536)...),
+		},
+		{name: "Nested tags", s: "a", tags: []uint64{1000, 1}, want: []byte{0xd9, 0x03, 0xe8, 0xc1, 0x61, 'a'}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := AppendText(nil, tc.s, tc.tags...)
+			if !bytes.Equal(got, tc.want) {
+				t.Errorf("AppendText() = %x, want %x", got, tc.want)
+			}
+
+			s, tags, err := DecodeText(got)
+			if err != nil {
+				t.Fatalf("DecodeText() returned an unexpected error: %v", err)
+			}
+			if s != tc.s || !reflect.DeepEqual(tags, tc.tags) {
+				t.Errorf("DecodeText() = (%q, %v), want (%q, %v)", s, tags, tc.s, tc.tags)
+			}
+		})
+	}
+}
+
+// TestDecodeText tests the decoding of other forms of text strings and the
+// rejection of malformed data items.
+func TestDecodeText(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []byte
+		want    string
+		wantErr error
+	}{
+		{
+			name: "Indefinite length",
+			data: []byte{0x7f, 0x65, 's', 't', 'r', 'e', 'a', 0x64, 'm', 'i', 'n', 'g', 0xff},
+			want: "streaming",
+		},
+		{name: "Empty indefinite length", data: []byte{0x7f, 0xff}, want: ""},
+		{name: "Non-shortest length", data: []byte{0x78, 0x01, 'a'}, want: "a"},
+		{name: "8-byte length", data: []byte{0x7b, 0, 0, 0, 0, 0, 0, 0, 1, 'a'}, want: "a"},
+		{name: "Byte string", data: []byte{0x41, 'a'}, wantErr: ErrUnexpectedType},
+		{name: "Integer", data: []byte{0x01}, wantErr: ErrUnexpectedType},
+		{name: "Tagged integer", data: []byte{0xd8, 0x20, 0x01}, wantErr: ErrUnexpectedType},
+		{name: "Empty data", data: nil, wantErr: ErrMalformed},
+		{name: "Truncated text", data: []byte{0x64, 'I', 'E'}, wantErr: ErrMalformed},
+		{name: "Truncated length", data: []byte{0x79, 0x01}, wantErr: ErrMalformed},
+		{name: "Huge length", data: append([]byte{0x7b}, bytes.Repeat([]byte{0xff}, 8)...), wantErr: ErrMalformed},
+		{name: "Tag without content", data: []byte{0xd8, 0x20}, wantErr: ErrMalformed},
+		{name: "Indefinite-length tag", data: []byte{0xdf, 0x61, 'a'}, wantErr: ErrMalformed},
+		{name: "Reserved additional information", data: []byte{0x7c}, wantErr: ErrMalformed},
+		{name: "Trailing data", data: []byte{0x61, 'a', 'b'}, wantErr: ErrMalformed},
+		{name: "Invalid UTF-8", data: []byte{0x61, 0xff}, wantErr: ErrMalformed},
+		{name: "Missing break", data: []byte{0x7f, 0x61, 'a'}, wantErr: ErrMalformed},
+		{name: "Byte string chunk", data: []byte{0x7f, 0x41, 'a', 0xff}, wantErr: ErrMalformed},
+		{name: "Nested indefinite chunk", data: []byte{0x7f, 0x7f, 0xff, 0xff}, wantErr: ErrMalformed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _, err := DecodeText(tc.data)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("DecodeText() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("DecodeText() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
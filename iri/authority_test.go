@@ -433,7 +433,7 @@ func TestParsePort(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := newPartParser(tt.unchecked)
-			err := p.parsePort(tt.port)
+			err := p.parsePort(tt.port, 0)
 
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("parsePort() error = %v, wantErr %v", err, tt.wantErr)
@@ -468,7 +468,7 @@ func TestParseUserinfo(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := newPartParser(tt.unchecked)
-			err := p.parseUserinfo(tt.userinfo)
+			err := p.parseUserinfo(tt.userinfo, 0)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("parseUserinfo() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -496,12 +496,15 @@ func TestParseHost(t *testing.T) {
 		{"invalid char", "bad/host", false, true, ""},
 		{"invalid percent-encoding", "a%2xb.com", false, true, ""},
 		{"invalid ip literal", "[not-an-ip]", false, true, ""},
+		{"bracket in reg-name", "a]b.com", false, true, ""},
+		{"colon in reg-name", "a:b.com", false, true, ""},
+		{"valid percent-encoded bracket in reg-name", "a%5Db.com", false, false, "a%5Db.com"},
 		{"unchecked with invalid char", "bad/host", true, false, "bad/host"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := newPartParser(tt.unchecked)
-			err := p.parseHost(tt.host)
+			err := p.parseHost(tt.host, 0)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("parseHost() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -560,6 +563,20 @@ func TestParseAuthority(t *testing.T) {
 			wantAuthority: 0,
 			wantRemainder: "/path",
 		},
+		{
+			name:          "empty userinfo keeps delimiter",
+			input:         "@example.com/path",
+			wantOutput:    "@example.com",
+			wantAuthority: 12,
+			wantRemainder: "/path",
+		},
+		{
+			name:          "empty port keeps delimiter",
+			input:         "example.com:/path",
+			wantOutput:    "example.com:",
+			wantAuthority: 12,
+			wantRemainder: "/path",
+		},
 		{
 			name:    "invalid userinfo (bad percent encoding)",
 			input:   "user%@host.com/path",
@@ -570,6 +587,18 @@ func TestParseAuthority(t *testing.T) {
 			input:   "example.com:bad/path",
 			wantErr: true,
 		},
+		{
+			name:    "characters between ip literal and port",
+			input:   "[::1]x:80/path",
+			wantErr: true,
+		},
+		{
+			name:          "ip literal with userinfo and port",
+			input:         "u@[::1]:80/path",
+			wantOutput:    "u@[::1]:80",
+			wantAuthority: 10,
+			wantRemainder: "/path",
+		},
 		{
 			name:    "truly invalid host",
 			input:   "bad{host}.com/path",
@@ -17,10 +17,13 @@ limitations under the License.
 package iri
 
 import (
+	"io"
 	"net"
 	"strings"
+	"unicode/utf8"
 
 	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
 )
 
 const (
@@ -29,23 +32,26 @@ const (
 	ipvFutureParts = 2
 )
 
-// parseUserinfo handles the userinfo part of the authority.
-func (p *iriParser) parseUserinfo(userinfo string) error {
+// parseUserinfo handles the userinfo part of the authority, which starts at
+// the start position of the input.
+func (p *iriParser) parseUserinfo(userinfo string, start int) error {
 	if userinfo == "" {
 		return nil
 	}
-	if !p.unchecked {
+	if !p.unchecked && !p.allowBidiMix {
 		if err := validateBidiComponent(userinfo); err != nil {
-			return err
+			return p.errorAt(start, err)
 		}
 	}
 
 	// Use a temporary buffer to ensure parsing is transactional.
 	var tempBuffer strings.Builder
 	tempParser := &iriParser{
-		input:     newParserInput(userinfo),
-		output:    &stringOutputBuffer{builder: &tempBuffer},
-		unchecked: p.unchecked,
+		input:        newParserInput(userinfo),
+		output:       &stringOutputBuffer{builder: &tempBuffer},
+		unchecked:    p.unchecked,
+		canonicalize: p.canonicalize,
+		inputOffset:  p.inputOffset + start,
 	}
 
 	for {
@@ -66,6 +72,7 @@ func (p *iriParser) parseUserinfo(userinfo string) error {
 }
 
 // validateHost checks the host component for structural validity (IP literal format, Bidi rules).
+// The Bidi rules are skipped when the parser allows mixed directions.
 func (p *iriParser) validateHost(host string) error {
 	if strings.HasPrefix(host, "[") {
 		if !strings.HasSuffix(host, "]") {
@@ -75,30 +82,36 @@ func (p *iriParser) validateHost(host string) error {
 		if err := p.validateIPLiteral(ipLiteral); err != nil {
 			return err
 		}
-	} else if err := validateBidiHost(host); err != nil {
-		return err
+	} else if !p.allowBidiMix {
+		if err := validateBidiHost(host); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-// parseHost handles the host part of the authority.
-func (p *iriParser) parseHost(host string) error {
+// parseHost handles the host part of the authority, which starts at the start
+// position of the input.
+func (p *iriParser) parseHost(host string, start int) error {
 	if host == "" {
 		return nil
 	}
 	if !p.unchecked {
 		if err := p.validateHost(host); err != nil {
-			return err
+			return p.errorAt(start, err)
 		}
 	}
 
 	var tempBuffer strings.Builder
 	tempParser := &iriParser{
-		input:     newParserInput(host),
-		output:    &stringOutputBuffer{builder: &tempBuffer},
-		unchecked: p.unchecked,
+		input:        newParserInput(host),
+		output:       &stringOutputBuffer{builder: &tempBuffer},
+		unchecked:    p.unchecked,
+		canonicalize: p.canonicalize,
+		inputOffset:  p.inputOffset + start,
 	}
 
+	isIPLiteral := strings.HasPrefix(host, "[")
 	// This is the correct "consume-then-process" loop.
 	for {
 		r, ok := tempParser.input.next()
@@ -114,31 +127,50 @@ func (p *iriParser) parseHost(host string) error {
 		} else {
 			// Check against the allowed character set for a host.
 			// The host component allows different characters depending on whether it's an
-			// IP literal or a registered name. We must check for all valid possibilities.
-			isIPLiteralChar := r == '[' || r == ']' || r == ':'
+			// IP literal or a registered name: brackets and colons are only allowed in the
+			// former, and anything else has to be percent-encoded in the latter.
+			isIPLiteralChar := isIPLiteral && (r == '[' || r == ']' || r == ':')
 			if !p.unchecked && !isIUnreservedOrSubDelims(r) && !isIPLiteralChar {
-				return &kindError{message: "Invalid character in host", char: r}
+				return tempParser.errorAt(
+					tempParser.input.position()-utf8.RuneLen(r),
+					&kindError{message: "Invalid character in host", char: r},
+				)
 			}
 			tempParser.output.writeRune(r)
 		}
 	}
 
+	if p.canonicalize {
+		// The unreserved characters were decoded while reading the host, so
+		// that they are lowercased like the literal ones, as Normalize does.
+		canonical, _ := normalizeHostAndPort(tempBuffer.String(), "", "")
+		p.output.writeString(uppercasePercentEncoding(norm.NFC.String(canonical)))
+		return nil
+	}
 	p.output.writeString(tempBuffer.String())
 	return nil
 }
 
-// parsePort handles the port part of the authority.
-func (p *iriParser) parsePort(port string) error {
+// parsePort handles the port part of the authority, which starts at the start
+// position of the input.
+func (p *iriParser) parsePort(port string, start int) error {
 	if port == "" {
 		return nil
 	}
 	if !p.unchecked {
-		for _, r := range port {
+		for i, r := range port {
 			if !isASCIIDigit(r) {
-				return &kindError{message: "Invalid port character", char: r}
+				return p.errorAt(start+i, &kindError{message: "Invalid port character", char: r})
 			}
 		}
 	}
+	if p.canonicalize {
+		// The scheme was lowercased when it was written.
+		scheme := p.output.string()[:max(p.outputPositions.SchemeEnd-1, 0)]
+		if _, port = normalizeHostAndPort("", port, scheme); port == "" {
+			return nil
+		}
+	}
 	p.output.writeRune(':')
 	p.output.writeString(port)
 	return nil
@@ -147,6 +179,7 @@ func (p *iriParser) parsePort(port string) error {
 // parseAuthority is a method on the iriParser that consumes and validates
 // the authority component from the input stream.
 func (p *iriParser) parseAuthority() error {
+	authorityStart := p.input.position()
 	authorityStr := p.input.asStr()
 	end := len(authorityStr)
 	for i, r := range authorityStr {
@@ -158,23 +191,60 @@ func (p *iriParser) parseAuthority() error {
 	authorityPart := authorityStr[:end]
 
 	userinfo, host, port := splitAuthority(authorityPart)
+	hostStart := authorityStart + strings.LastIndex(authorityPart, "@") + 1
+	if !p.unchecked {
+		hostport := authorityPart[hostStart-authorityStart:]
+		if err := checkIPLiteralEnd(hostport); err != nil {
+			return p.errorAt(hostStart+strings.LastIndex(hostport, "]")+1, err)
+		}
+	}
 
-	if err := p.parseUserinfo(userinfo); err != nil {
+	if err := p.parseUserinfo(userinfo, authorityStart); err != nil {
 		return err
 	}
-	if err := p.parseHost(host); err != nil {
+	// An empty userinfo ("//@host") still carries its delimiter, which must be
+	// kept so that the output positions stay aligned with the input.
+	// Normalize drops both delimiters.
+	if userinfo == "" && strings.Contains(authorityPart, "@") && !p.canonicalize {
+		p.output.writeRune('@')
+	}
+	p.outputUserInfoEnd = p.output.len()
+	if err := p.parseHost(host, hostStart); err != nil {
 		return err
 	}
-	if err := p.parsePort(port); err != nil {
+	p.outputHostEnd = p.output.len()
+	if err := p.parsePort(port, hostStart+len(host)+1); err != nil {
 		return err
 	}
+	// Same for an empty port ("//host:").
+	if port == "" && strings.HasSuffix(authorityPart, ":") && !strings.HasSuffix(host, ":") && !p.canonicalize {
+		p.output.writeRune(':')
+	}
 
-	p.input.reset(authorityStr[end:])
+	// Skip the authority without resetting the input, so that positions stay
+	// relative to the start of the IRI.
+	_, _ = p.input.reader.Seek(int64(authorityStart+end), io.SeekStart)
 	p.outputPositions.AuthorityEnd = p.output.len()
 
 	return nil
 }
 
+// checkIPLiteralEnd checks that an IP literal at the start of hostport is only
+// followed by a port, since splitAuthority silently drops anything else (e.g.,
+// the "x" of "[::1]x").
+func checkIPLiteralEnd(hostport string) error {
+	if !strings.HasPrefix(hostport, "[") {
+		return nil
+	}
+	end := strings.LastIndex(hostport, "]")
+	if end == -1 || end+1 == len(hostport) || hostport[end+1] == ':' {
+		// An unterminated literal is reported by validateHost.
+		return nil
+	}
+	r, _ := utf8.DecodeRuneInString(hostport[end+1:])
+	return &kindError{message: "Invalid character in host", char: r}
+}
+
 // validateIPLiteral checks if a string inside brackets is a valid IPv6 or IPvFuture address.
 func (p *iriParser) validateIPLiteral(ipLiteral string) error {
 	if strings.HasPrefix(ipLiteral, "v") || strings.HasPrefix(ipLiteral, "V") {
@@ -212,6 +282,20 @@ func (p *iriParser) validateIPVFuture(ip string) error {
 	return nil
 }
 
+// defaultPorts maps a lowercase scheme to its default port, as defined by the
+// scheme's specification. It drives the scheme-based normalization of
+// RFC 3986, Section 6.2.3, which removes a port equal to the default one.
+// Schemes that are not listed keep their port unchanged.
+//
+//nolint:gochecknoglobals // A read-only lookup table shared by all normalizations.
+var defaultPorts = map[string]string{
+	"ftp":   "21",
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+}
+
 // splitAuthority is the single, stateless utility function that parses an authority
 // string into its userinfo, host, and port components.
 func splitAuthority(authority string) (string, string, string) {
@@ -273,15 +357,8 @@ func normalizeHostAndPort(host, port, scheme string) (string, string) {
 
 	// Scheme-based port normalization.
 	normalizedPort := port
-	if normalizedPort != "" {
-		isDefaultPort := (scheme == "http" && normalizedPort == "80") ||
-			(scheme == "https" && normalizedPort == "443") ||
-			(scheme == "ftp" && normalizedPort == "21") ||
-			(scheme == "ws" && normalizedPort == "80") ||
-			(scheme == "wss" && normalizedPort == "443")
-		if isDefaultPort {
-			normalizedPort = ""
-		}
+	if defaultPort, ok := defaultPorts[scheme]; ok && normalizedPort == defaultPort {
+		normalizedPort = ""
 	}
 
 	return normalizedHost, normalizedPort
@@ -16,7 +16,10 @@ limitations under the License.
 
 package iri
 
-import "strings"
+import (
+	"strings"
+	"unicode/utf8"
+)
 
 // outputBuffer is an interface for building the output string during parsing.
 // This abstraction allows the parser to be used in different modes, such as
@@ -100,3 +103,40 @@ func (b *stringOutputBuffer) truncate(n int) {
 
 // reset clears the underlying strings.Builder.
 func (b *stringOutputBuffer) reset() { b.builder.Reset() }
+
+// bytesOutputBuffer is an implementation of outputBuffer backed by a byte
+// slice. It is useful when the output is ultimately needed as []byte, for
+// example to be written to an io.Writer or appended to a larger buffer,
+// as it avoids a string-to-bytes conversion. It is used by
+// Ref.AppendResolved.
+type bytesOutputBuffer struct {
+	buf []byte
+}
+
+// writeRune appends the UTF-8 encoding of a single rune to the slice.
+func (b *bytesOutputBuffer) writeRune(r rune) { b.buf = utf8.AppendRune(b.buf, r) }
+
+// writeString appends a string to the slice.
+func (b *bytesOutputBuffer) writeString(s string) { b.buf = append(b.buf, s...) }
+
+// string returns the complete content of the buffer as a string.
+func (b *bytesOutputBuffer) string() string { return string(b.buf) }
+
+// len returns the number of bytes currently in the buffer.
+func (b *bytesOutputBuffer) len() int { return len(b.buf) }
+
+// truncate reduces the buffer to n bytes by reslicing, keeping the capacity.
+// If n is invalid, the buffer is not modified.
+func (b *bytesOutputBuffer) truncate(n int) {
+	if n < 0 || n > len(b.buf) {
+		return
+	}
+	b.buf = b.buf[:n]
+}
+
+// reset clears the buffer while keeping its capacity for reuse.
+func (b *bytesOutputBuffer) reset() { b.buf = b.buf[:0] }
+
+// bytes returns the content of the buffer. The returned slice aliases the
+// buffer and is only valid until the next write.
+func (b *bytesOutputBuffer) bytes() []byte { return b.buf }
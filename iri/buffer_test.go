@@ -212,3 +212,102 @@ func TestStringOutputBuffer_Reset(t *testing.T) {
 		t.Errorf("String after reset should be empty, got '%s'", b.string())
 	}
 }
+
+// The bytesOutputBuffer must behave exactly like the stringOutputBuffer while
+// exposing its content as a byte slice.
+
+func TestBytesOutputBuffer_WriteOperations(t *testing.T) {
+	b := &bytesOutputBuffer{}
+
+	if b.len() != 0 || b.string() != "" {
+		t.Errorf("Initial buffer should be empty, got len %d, str '%s'", b.len(), b.string())
+	}
+
+	// RFC 3987, Section 2.2: Test with a multi-byte ucschar character 'é'.
+	b.writeRune('a')
+	b.writeRune('é')
+	b.writeString("http:")
+	expectedStr := "aéhttp:"
+	if b.len() != len(expectedStr) {
+		t.Errorf("Length should be %d, got %d", len(expectedStr), b.len())
+	}
+	if b.string() != expectedStr {
+		t.Errorf("String should be '%s', got '%s'", expectedStr, b.string())
+	}
+	if string(b.bytes()) != expectedStr {
+		t.Errorf("Bytes should be '%s', got '%s'", expectedStr, string(b.bytes()))
+	}
+}
+
+func TestBytesOutputBuffer_Truncate(t *testing.T) {
+	b := &bytesOutputBuffer{}
+	// A full IRI reference as per RFC 3986, Appendix A.
+	b.writeString("scheme://user@host:123/path?query#fragment")
+
+	// Truncate to a smaller valid length.
+	// "scheme://user@" has a length of 14.
+	b.truncate(14)
+	if b.len() != 14 {
+		t.Errorf("Length after truncating to 14 should be 14, got %d", b.len())
+	}
+	if b.string() != "scheme://user@" {
+		t.Errorf("String after truncating should be 'scheme://user@', got '%s'", b.string())
+	}
+
+	// Truncate to 0.
+	b.truncate(0)
+	if b.len() != 0 {
+		t.Errorf("Length after truncating to 0 should be 0, got %d", b.len())
+	}
+	if b.string() != "" {
+		t.Errorf("String after truncating to 0 should be empty, got '%s'", b.string())
+	}
+
+	// Reset and test invalid truncate values.
+	b.writeString("test") // Length 4
+	b.truncate(-1)
+	if b.len() != 4 || b.string() != "test" {
+		t.Errorf("Truncating to negative value should be a no-op, got len %d, str '%s'", b.len(), b.string())
+	}
+	b.truncate(5)
+	if b.len() != 4 || b.string() != "test" {
+		t.Errorf("Truncating to a larger value should be a no-op, got len %d, str '%s'", b.len(), b.string())
+	}
+
+	// Test truncating to the same length.
+	b.truncate(4)
+	if b.len() != 4 || b.string() != "test" {
+		t.Errorf("Truncating to the same length should be a no-op, got len %d, str '%s'", b.len(), b.string())
+	}
+}
+
+func TestBytesOutputBuffer_Reset(t *testing.T) {
+	b := &bytesOutputBuffer{}
+	b.writeString("some-initial-data")
+	capacity := cap(b.bytes())
+
+	b.reset()
+	if b.len() != 0 || b.string() != "" {
+		t.Errorf("Buffer should be empty after reset, got len %d, str '%s'", b.len(), b.string())
+	}
+	if cap(b.bytes()) != capacity {
+		t.Errorf("Capacity should be kept after reset, got %d, want %d", cap(b.bytes()), capacity)
+	}
+}
+
+// TestBytesOutputBuffer_Run tests that the parser produces the same output
+// with a bytesOutputBuffer as with a stringOutputBuffer.
+func TestBytesOutputBuffer_Run(t *testing.T) {
+	input := "http://example.com/a b?q#f"
+	sb := &stringOutputBuffer{builder: &strings.Builder{}}
+	bb := &bytesOutputBuffer{}
+
+	posString, errString := run(input, nil, false, sb)
+	posBytes, errBytes := run(input, nil, false, bb)
+	if errString != nil || errBytes != nil {
+		t.Fatalf("run() returned unexpected errors: %v, %v", errString, errBytes)
+	}
+	if posString != posBytes || sb.string() != string(bb.bytes()) {
+		t.Errorf("Outputs differ: '%s' %+v vs '%s' %+v", sb.string(), posString, bb.bytes(), posBytes)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iri
+
+import "strings"
+
+// Builder assembles an IRI reference from its individual components. Each
+// setter returns the Builder so that calls can be chained, and Build validates
+// the assembled string with ParseRef. The zero value is an empty Builder ready
+// to use.
+//
+// Components are written as-is, without any percent-encoding, so each value
+// must already be valid for its position in the IRI. A component is only
+// emitted if its setter was called: SetQuery("") produces a trailing "?",
+// whereas never calling SetQuery produces no query at all.
+type Builder struct {
+	scheme       string
+	authority    string
+	path         string
+	query        string
+	fragment     string
+	hasScheme    bool
+	hasAuthority bool
+	hasQuery     bool
+	hasFragment  bool
+}
+
+// NewBuilder returns a new, empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// SetScheme sets the scheme component (e.g., "http"), without the trailing ':'.
+func (b *Builder) SetScheme(scheme string) *Builder {
+	b.scheme = scheme
+	b.hasScheme = true
+	return b
+}
+
+// SetAuthority sets the authority component (e.g., "user@example.com:8080"),
+// without the leading "//".
+func (b *Builder) SetAuthority(authority string) *Builder {
+	b.authority = authority
+	b.hasAuthority = true
+	return b
+}
+
+// SetPath sets the path component.
+func (b *Builder) SetPath(path string) *Builder {
+	b.path = path
+	return b
+}
+
+// SetQuery sets the query component, without the leading '?'.
+func (b *Builder) SetQuery(query string) *Builder {
+	b.query = query
+	b.hasQuery = true
+	return b
+}
+
+// SetFragment sets the fragment component, without the leading '#'.
+func (b *Builder) SetFragment(fragment string) *Builder {
+	b.fragment = fragment
+	b.hasFragment = true
+	return b
+}
+
+// String returns the assembled IRI reference without validating it.
+func (b *Builder) String() string {
+	var sb strings.Builder
+	if b.hasScheme {
+		sb.WriteString(b.scheme)
+		sb.WriteRune(':')
+	}
+	if b.hasAuthority {
+		sb.WriteString("//")
+		sb.WriteString(b.authority)
+	}
+	sb.WriteString(b.path)
+	if b.hasQuery {
+		sb.WriteRune('?')
+		sb.WriteString(b.query)
+	}
+	if b.hasFragment {
+		sb.WriteRune('#')
+		sb.WriteString(b.fragment)
+	}
+	return sb.String()
+}
+
+// Build assembles the components and validates the result as an IRI reference.
+// In addition to the checks performed by ParseRef, it rejects component
+// combinations whose meaning would change once concatenated, such as a path
+// starting with "//" without an authority, a relative path following an
+// authority, or a query containing '#'.
+func (b *Builder) Build() (*Ref, error) {
+	if !b.hasAuthority && strings.HasPrefix(b.path, "//") {
+		return nil, newParseError(errPathStartingWithSlashes)
+	}
+	if b.hasAuthority && b.path != "" && !strings.HasPrefix(b.path, "/") {
+		return nil, newParseError(errPathNotAbsoluteWithAuthority)
+	}
+
+	s := b.String()
+	ref, err := ParseRef(s)
+	if err != nil {
+		return nil, err
+	}
+	if !b.matches(ref) {
+		return nil, newParseError(&kindError{message: "IRI components are ambiguous once assembled", details: s})
+	}
+	return ref, nil
+}
+
+// BuildIri is like Build but requires a scheme, returning an absolute Iri.
+func (b *Builder) BuildIri() (*Iri, error) {
+	if !b.hasScheme {
+		return nil, newParseError(errNoScheme)
+	}
+	ref, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return NewIriFromRef(ref)
+}
+
+// matches reports whether the components of a parsed Ref are exactly the ones
+// that were set on the Builder.
+func (b *Builder) matches(ref *Ref) bool {
+	scheme, hasScheme := ref.Scheme()
+	authority, hasAuthority := ref.Authority()
+	query, hasQuery := ref.Query()
+	fragment, hasFragment := ref.Fragment()
+	return scheme == b.scheme && hasScheme == b.hasScheme &&
+		authority == b.authority && hasAuthority == b.hasAuthority &&
+		ref.Path() == b.path &&
+		query == b.query && hasQuery == b.hasQuery &&
+		fragment == b.fragment && hasFragment == b.hasFragment
+}
+
+// toBuilder returns a Builder pre-populated with the components of the Ref.
+func (r *Ref) toBuilder() *Builder {
+	b := &Builder{path: r.Path()}
+	b.scheme, b.hasScheme = r.Scheme()
+	b.authority, b.hasAuthority = r.Authority()
+	b.query, b.hasQuery = r.Query()
+	b.fragment, b.hasFragment = r.Fragment()
+	return b
+}
+
+// WithScheme returns a new Iri with the scheme replaced and all other
+// components preserved. It returns a *ParseError if the result is invalid,
+// for example if the new scheme does not start with a letter.
+func (i *Iri) WithScheme(scheme string) (*Iri, error) {
+	return i.toBuilder().SetScheme(scheme).BuildIri()
+}
+
+// WithAuthority returns a new Iri with the authority replaced (without the
+// leading "//") and all other components preserved.
+func (i *Iri) WithAuthority(authority string) (*Iri, error) {
+	return i.toBuilder().SetAuthority(authority).BuildIri()
+}
+
+// WithPath returns a new Iri with the path replaced and all other components
+// preserved.
+func (i *Iri) WithPath(path string) (*Iri, error) {
+	return i.toBuilder().SetPath(path).BuildIri()
+}
+
+// WithQuery returns a new Iri with the query replaced (without the leading
+// '?') and all other components preserved. An empty query keeps the '?'
+// delimiter; use WithoutQuery to remove the query entirely.
+func (i *Iri) WithQuery(query string) (*Iri, error) {
+	return i.toBuilder().SetQuery(query).BuildIri()
+}
+
+// WithoutQuery returns a new Iri with the query component, including its '?'
+// delimiter, removed.
+func (i *Iri) WithoutQuery() (*Iri, error) {
+	b := i.toBuilder()
+	b.query, b.hasQuery = "", false
+	return b.BuildIri()
+}
+
+// WithFragment returns a new Iri with the fragment replaced (without the
+// leading '#') and all other components preserved.
+func (i *Iri) WithFragment(fragment string) (*Iri, error) {
+	return i.toBuilder().SetFragment(fragment).BuildIri()
+}
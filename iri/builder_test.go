@@ -0,0 +1,203 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//nolint:testpackage // This is a white-box test file for an internal package. It needs to be in the same package to test unexported functions.
+package iri
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestBuilder_Build tests assembling IRI references from their components.
+func TestBuilder_Build(t *testing.T) {
+	testCases := []struct {
+		name     string
+		builder  *Builder
+		expected string
+	}{
+		{
+			name: "All components",
+			builder: NewBuilder().SetScheme("http").SetAuthority("user@example.com:8080").
+				SetPath("/a/b").SetQuery("q=1").SetFragment("frag"),
+			expected: "http://user@example.com:8080/a/b?q=1#frag",
+		},
+		{
+			name:     "Scheme and path only",
+			builder:  NewBuilder().SetScheme("urn").SetPath("isbn:0451450523"),
+			expected: "urn:isbn:0451450523",
+		},
+		{
+			name:     "Empty query and fragment are emitted",
+			builder:  NewBuilder().SetPath("/a").SetQuery("").SetFragment(""),
+			expected: "/a?#",
+		},
+		{
+			name:     "Network-path reference",
+			builder:  NewBuilder().SetAuthority("example.com").SetPath("/a"),
+			expected: "//example.com/a",
+		},
+		{
+			name:     "Relative path",
+			builder:  NewBuilder().SetPath("../a").SetFragment("b"),
+			expected: "../a#b",
+		},
+		{
+			name:     "Empty builder",
+			builder:  &Builder{},
+			expected: "",
+		},
+		{
+			name:     "Path with // after authority",
+			builder:  NewBuilder().SetScheme("http").SetAuthority("a").SetPath("//b"),
+			expected: "http://a//b",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ref, err := tc.builder.Build()
+			if err != nil {
+				t.Fatalf("Build() returned an unexpected error: %v", err)
+			}
+			if ref.String() != tc.expected {
+				t.Errorf("Build() = '%s', want '%s'", ref.String(), tc.expected)
+			}
+		})
+	}
+}
+
+// TestBuilder_Build_Invalid tests that invalid or ambiguous components are rejected.
+func TestBuilder_Build_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		builder *Builder
+		wantErr error
+	}{
+		{
+			name:    "Path starting with // without authority",
+			builder: NewBuilder().SetScheme("http").SetPath("//a"),
+			wantErr: errPathStartingWithSlashes,
+		},
+		{
+			name:    "Relative path after authority",
+			builder: NewBuilder().SetScheme("http").SetAuthority("a").SetPath("b"),
+			wantErr: errPathNotAbsoluteWithAuthority,
+		},
+		{name: "Invalid scheme", builder: NewBuilder().SetScheme("1http").SetPath("/a")},
+		{name: "Colon in first segment without scheme", builder: NewBuilder().SetPath("a:b")},
+		{name: "Query containing a fragment delimiter", builder: NewBuilder().SetPath("/a").SetQuery("b#c")},
+		{name: "Path containing a query delimiter", builder: NewBuilder().SetPath("/a?b")},
+		{name: "Invalid character", builder: NewBuilder().SetPath("/a[b")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.builder.Build()
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expected a *ParseError, got %v", err)
+			}
+			if tc.wantErr != nil && parseErr.Message != tc.wantErr.Error() {
+				t.Errorf("Expected error message '%s', got '%s'", tc.wantErr.Error(), parseErr.Message)
+			}
+		})
+	}
+}
+
+// TestBuilder_BuildIri tests that BuildIri requires a scheme.
+func TestBuilder_BuildIri(t *testing.T) {
+	i, err := NewBuilder().SetScheme("https").SetAuthority("example.com").SetPath("/").BuildIri()
+	if err != nil {
+		t.Fatalf("BuildIri() returned an unexpected error: %v", err)
+	}
+	if i.Scheme() != "https" || i.String() != "https://example.com/" {
+		t.Errorf("BuildIri() = '%s', want 'https://example.com/'", i)
+	}
+
+	_, err = NewBuilder().SetPath("/a").BuildIri()
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Message != errNoScheme.Error() {
+		t.Errorf("Expected a missing scheme error, got %v", err)
+	}
+
+	if _, err = NewBuilder().SetScheme("http").SetPath("//a").BuildIri(); err == nil {
+		t.Error("Expected an error for an invalid path, but got none")
+	}
+}
+
+// TestIri_WithComponent tests replacing a single component of an Iri.
+func TestIri_WithComponent(t *testing.T) {
+	const original = "http://example.com/a/b?q=1#frag"
+	testCases := []struct {
+		name     string
+		apply    func(i *Iri) (*Iri, error)
+		expected string
+	}{
+		{"WithScheme", func(i *Iri) (*Iri, error) { return i.WithScheme("https") }, "https://example.com/a/b?q=1#frag"},
+		{
+			"WithAuthority",
+			func(i *Iri) (*Iri, error) { return i.WithAuthority("u@h:8080") },
+			"http://u@h:8080/a/b?q=1#frag",
+		},
+		{"WithPath", func(i *Iri) (*Iri, error) { return i.WithPath("/c") }, "http://example.com/c?q=1#frag"},
+		{"WithPath empty", func(i *Iri) (*Iri, error) { return i.WithPath("") }, "http://example.com?q=1#frag"},
+		{"WithQuery", func(i *Iri) (*Iri, error) { return i.WithQuery("x=y") }, "http://example.com/a/b?x=y#frag"},
+		{"WithQuery empty", func(i *Iri) (*Iri, error) { return i.WithQuery("") }, "http://example.com/a/b?#frag"},
+		{"WithoutQuery", func(i *Iri) (*Iri, error) { return i.WithoutQuery() }, "http://example.com/a/b#frag"},
+		{"WithFragment", func(i *Iri) (*Iri, error) { return i.WithFragment("top") }, "http://example.com/a/b?q=1#top"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			i := mustParseIri(t, original)
+			result, err := tc.apply(i)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.String() != tc.expected {
+				t.Errorf("Expected '%s', got '%s'", tc.expected, result.String())
+			}
+			if i.String() != original {
+				t.Errorf("Original Iri was modified to '%s'", i.String())
+			}
+		})
+	}
+}
+
+// TestIri_WithComponent_Invalid tests that invalid replacements are rejected.
+func TestIri_WithComponent_Invalid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		apply func(i *Iri) (*Iri, error)
+	}{
+		{"Scheme starting with a digit", func(i *Iri) (*Iri, error) { return i.WithScheme("1bad") }},
+		{"Empty scheme", func(i *Iri) (*Iri, error) { return i.WithScheme("") }},
+		{"Relative path with authority", func(i *Iri) (*Iri, error) { return i.WithPath("c") }},
+		{"Invalid port", func(i *Iri) (*Iri, error) { return i.WithAuthority("h:port") }},
+		{"Query with fragment delimiter", func(i *Iri) (*Iri, error) { return i.WithQuery("a#b") }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.apply(mustParseIri(t, "http://example.com/a"))
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Errorf("Expected a *ParseError, got %v", err)
+			}
+		})
+	}
+}
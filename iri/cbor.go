@@ -0,0 +1,45 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iri
+
+import (
+	"fmt"
+
+	"github.com/jplu/trident/internal/cbor"
+)
+
+// MarshalCBOR implements the cbor.Marshaler interface of CBOR libraries such
+// as github.com/fxamacker/cbor, encoding the IRI as a text string with tag 32,
+// the tag of URIs in RFC 8949, Section 3.4.5.3.
+func (i *Iri) MarshalCBOR() ([]byte, error) {
+	return cbor.AppendText(nil, i.iri, cbor.TagURI), nil
+}
+
+// UnmarshalCBOR implements the cbor.Unmarshaler interface of CBOR libraries.
+// It accepts a text string, with or without tag 32, and, like UnmarshalJSON,
+// validates it with ParseIri, so a relative reference results in a
+// *ParseError.
+func (i *Iri) UnmarshalCBOR(data []byte) error {
+	s, tags, err := cbor.DecodeText(data)
+	if err != nil {
+		return err
+	}
+	if len(tags) > 1 || (len(tags) == 1 && tags[0] != cbor.TagURI) {
+		return fmt.Errorf("%w: unexpected tag %v for an IRI", cbor.ErrUnexpectedType, tags)
+	}
+	return i.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iri
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jplu/trident/internal/cbor"
+)
+
+// TestIri_CBOR tests encoding an Iri as a CBOR text string with tag 32, and
+// decoding it back.
+func TestIri_CBOR(t *testing.T) {
+	in := mustParseIri(t, "http://www.example.com")
+	data, err := in.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR() returned an unexpected error: %v", err)
+	}
+	// Example of RFC 8949, Appendix A.
+	want := append([]byte{0xd8, 0x20, 0x76}, "http://www.example.com"...)
+	if !bytes.Equal(data, want) {
+		t.Errorf("MarshalCBOR() = %x, want %x", data, want)
+	}
+
+	for _, s := range []string{"http://www.example.com", "http://example.com/résumé?a=1#f", "urn:isbn:0451450523"} {
+		t.Run(s, func(t *testing.T) {
+			orig := mustParseIri(t, s)
+			encoded, marshalErr := orig.MarshalCBOR()
+			if marshalErr != nil {
+				t.Fatalf("MarshalCBOR() returned an unexpected error: %v", marshalErr)
+			}
+			var out Iri
+			if unmarshalErr := out.UnmarshalCBOR(encoded); unmarshalErr != nil {
+				t.Fatalf("UnmarshalCBOR() returned an unexpected error: %v", unmarshalErr)
+			}
+			if !out.Equal(orig) {
+				t.Errorf("Round trip produced %s, want %s", out.String(), orig.String())
+			}
+		})
+	}
+
+	t.Run("Untagged text string", func(t *testing.T) {
+		var out Iri
+		if err = out.UnmarshalCBOR(cbor.AppendText(nil, "urn:a")); err != nil {
+			t.Fatalf("UnmarshalCBOR() returned an unexpected error: %v", err)
+		}
+		if out.String() != "urn:a" {
+			t.Errorf("UnmarshalCBOR() = %s, want urn:a", out.String())
+		}
+	})
+}
+
+// TestIri_UnmarshalCBOR_Invalid tests that decoding rejects relative and
+// malformed IRIs, other tags, and other data items.
+func TestIri_UnmarshalCBOR_Invalid(t *testing.T) {
+	testCases := []struct {
+		name      string
+		data      []byte
+		wantParse bool
+		wantErr   error
+	}{
+		{name: "Relative reference", data: cbor.AppendText(nil, "/a/b", cbor.TagURI), wantParse: true},
+		{name: "Malformed IRI", data: cbor.AppendText(nil, "http://example.com/[", cbor.TagURI), wantParse: true},
+		{name: "Empty text", data: cbor.AppendText(nil, ""), wantParse: true},
+		{name: "Other tag", data: cbor.AppendText(nil, "urn:a", 0), wantErr: cbor.ErrUnexpectedType},
+		{
+			name:    "Nested tags",
+			data:    cbor.AppendText(nil, "urn:a", cbor.TagURI, cbor.TagURI),
+			wantErr: cbor.ErrUnexpectedType,
+		},
+		{name: "Byte string", data: []byte{0x41, 'a'}, wantErr: cbor.ErrUnexpectedType},
+		{name: "Truncated", data: []byte{0xd8, 0x20, 0x65, 'u'}, wantErr: cbor.ErrMalformed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out Iri
+			err := out.UnmarshalCBOR(tc.data)
+			var parseErr *ParseError
+			if tc.wantParse && !errors.As(err, &parseErr) {
+				t.Errorf("Expected a *ParseError, got %v", err)
+			}
+			if !tc.wantParse && !errors.Is(err, tc.wantErr) {
+				t.Errorf("UnmarshalCBOR() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
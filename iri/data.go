@@ -0,0 +1,86 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iri
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+const (
+	// defaultDataMediaType is the media type of a "data" URL that omits it,
+	// as per RFC 2397, Section 2.
+	defaultDataMediaType = "text/plain;charset=US-ASCII"
+	// base64DataExtension is the parameter marking a base64-encoded payload.
+	base64DataExtension = ";base64"
+)
+
+// DataURL extracts the content of a "data" URL, whose syntax is defined by
+// RFC 2397 as "data:[<mediatype>][;base64],<data>". It returns the media type,
+// a boolean indicating whether the payload was base64-encoded, and the
+// decoded payload.
+//
+// The media type defaults to "text/plain;charset=US-ASCII" when omitted, and
+// to "text/plain" when only parameters are given (e.g., "data:;charset=utf-8,").
+// The payload is percent-decoded, then base64-decoded if needed. The fragment,
+// if any, is not part of the payload.
+//
+// The "data" structure is not checked by the parsing functions, so an error is
+// only reported here, for instance if the ',' separator is missing.
+func (r *Ref) DataURL() (string, bool, []byte, error) {
+	scheme, ok := r.Scheme()
+	if !ok || !strings.EqualFold(scheme, "data") {
+		return "", false, nil, newParseError(&kindError{message: "Not a data URL", details: r.iri})
+	}
+
+	// The payload may contain '?', so the query is part of it.
+	content := r.iri[r.positions.SchemeEnd:r.positions.QueryEnd]
+	metadata, data, found := strings.Cut(content, ",")
+	if !found {
+		return "", false, nil, newParseError(&kindError{message: "Invalid data URL: missing ','", details: r.iri})
+	}
+
+	mediaType, err := percentDecode(metadata)
+	if err != nil {
+		return "", false, nil, newParseError(err)
+	}
+	isBase64 := len(mediaType) >= len(base64DataExtension) &&
+		strings.EqualFold(mediaType[len(mediaType)-len(base64DataExtension):], base64DataExtension)
+	if isBase64 {
+		mediaType = mediaType[:len(mediaType)-len(base64DataExtension)]
+	}
+	switch {
+	case mediaType == "":
+		mediaType = defaultDataMediaType
+	case strings.HasPrefix(mediaType, ";"):
+		mediaType = "text/plain" + mediaType
+	}
+
+	decoded, err := percentDecode(data)
+	if err != nil {
+		return "", false, nil, newParseError(err)
+	}
+	if !isBase64 {
+		return mediaType, false, []byte(decoded), nil
+	}
+	payload, err := base64.StdEncoding.DecodeString(decoded)
+	if err != nil {
+		kind := &kindError{message: "Invalid data URL base64 payload", details: err.Error()}
+		return "", false, nil, newParseError(kind)
+	}
+	return mediaType, true, payload, nil
+}
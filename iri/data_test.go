@@ -0,0 +1,90 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//nolint:testpackage // This is a white-box test file for an internal package. It needs to be in the same package to test unexported functions.
+package iri
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestRef_DataURL tests the extraction of the content of data URLs, using the
+// examples of RFC 2397, Section 4 among others.
+func TestRef_DataURL(t *testing.T) {
+	testCases := []struct {
+		name      string
+		iri       string
+		mediaType string
+		isBase64  bool
+		payload   string
+	}{
+		{"Default media type", "data:,A%20brief%20note", "text/plain;charset=US-ASCII", false, "A brief note"},
+		{"Parameters only", "data:;charset=utf-8,%C3%A9", "text/plain;charset=utf-8", false, "é"},
+		{
+			"Charset parameter",
+			"data:text/plain;charset=iso-8859-7,%be%f0%be",
+			"text/plain;charset=iso-8859-7", false, "\xbe\xf0\xbe",
+		},
+		{"Base64 payload", "data:text/plain;base64,SGVsbG8sIFdvcmxkIQ==", "text/plain", true, "Hello, World!"},
+		{"Base64 marker is case-insensitive", "data:;BASE64,SGk=", "text/plain;charset=US-ASCII", true, "Hi"},
+		{"Empty payload", "data:text/html,", "text/html", false, ""},
+		{"Payload with comma and query", "data:,a,b?c=d#frag", "text/plain;charset=US-ASCII", false, "a,b?c=d"},
+		{"Uppercase scheme", "DATA:image/gif;base64,R0lG", "image/gif", true, "GIF"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mediaType, isBase64, payload, err := mustParseRef(t, tc.iri).DataURL()
+			if err != nil {
+				t.Fatalf("DataURL() returned an unexpected error: %v", err)
+			}
+			if mediaType != tc.mediaType {
+				t.Errorf("Expected media type %q, got %q", tc.mediaType, mediaType)
+			}
+			if isBase64 != tc.isBase64 {
+				t.Errorf("Expected isBase64 %v, got %v", tc.isBase64, isBase64)
+			}
+			if string(payload) != tc.payload {
+				t.Errorf("Expected payload %q, got %q", tc.payload, payload)
+			}
+		})
+	}
+}
+
+// TestRef_DataURL_Invalid tests the errors reported for invalid data URLs.
+func TestRef_DataURL_Invalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		iri  string
+	}{
+		{"Other scheme", "http://example.com/a,b"},
+		{"Relative reference", "a,b"},
+		{"Missing comma", "data:text/plain;base64"},
+		{"Invalid base64", "data:;base64,SGk"},
+		{"Comma only in fragment", "data:text/plain#a,b"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, err := mustParseRef(t, tc.iri).DataURL()
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Errorf("Expected a *ParseError, got %v", err)
+			}
+		})
+	}
+}
@@ -25,6 +25,90 @@ import (
 	"unicode/utf8"
 )
 
+// upperHexDigits are the digits used to percent-encode octets, uppercase as
+// recommended by RFC 3986, Section 2.1.
+const upperHexDigits = "0123456789ABCDEF"
+
+// EncodePathSegment percent-encodes s so that it can be used as a single
+// segment of an IRI path, e.g. "a b/c" becomes "a%20b%2Fc". Unlike
+// url.PathEscape, which follows the URI rules, it keeps every character
+// allowed in an IRI path segment (ipchar in RFC 3987, Section 2.2), so
+// non-ASCII letters such as "é" are left as they are. All other characters,
+// including "/", "?", "#", "%", spaces, control characters and bidi formatting
+// characters, are percent-encoded as UTF-8 octets, and so are the bytes of
+// invalid UTF-8 sequences. The dot-segments "." and ".." are not encoded.
+func EncodePathSegment(s string) string {
+	return encodeString(s, isPathSegmentChar)
+}
+
+// EncodeQueryComponent percent-encodes s so that it can be used as a key or a
+// value of an IRI query, e.g. "a&b=c" becomes "a%26b%3Dc". It keeps the
+// characters allowed in an IRI query (RFC 3987, Section 2.2), including
+// non-ASCII ones, except "&", "=" and "+", which delimit or encode the pairs
+// decoded by Ref.QueryParams. Spaces are encoded as "%20". All other
+// characters are percent-encoded as with EncodePathSegment.
+func EncodeQueryComponent(s string) string {
+	return encodeString(s, isQueryComponentChar)
+}
+
+// Decode decodes every percent-encoded octet of s, e.g. "a%20b" becomes
+// "a b". It is the inverse of EncodePathSegment and EncodeQueryComponent. No
+// other transformation is applied; in particular, "+" is not decoded to a
+// space. The decoded octets are not required to form valid UTF-8. It returns
+// a *ParseError if a "%" is not followed by two hexadecimal digits.
+func Decode(s string) (string, error) {
+	decoded, err := percentDecode(s)
+	if err != nil {
+		return "", newParseError(err)
+	}
+	return decoded, nil
+}
+
+// isPathSegmentChar is a predicate for the characters allowed as-is in a path
+// segment.
+func isPathSegmentChar(c rune) bool {
+	return isIUnreservedOrSubDelims(c) || c == ':' || c == '@'
+}
+
+// isQueryComponentChar is a predicate for the characters allowed as-is in a
+// query key or value.
+func isQueryComponentChar(c rune) bool {
+	return isQueryChar(c) && c != '&' && c != '=' && c != '+'
+}
+
+// encodeString percent-encodes the characters of s that are not allowed by
+// the given predicate, as well as the bytes of invalid UTF-8 sequences. It
+// returns s itself if nothing needs to be encoded.
+func encodeString(s string, allowed func(rune) bool) string {
+	var b strings.Builder
+	encoded := false
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r != utf8.RuneError || size > 1) && allowed(r) {
+			if encoded {
+				b.WriteString(s[i : i+size])
+			}
+			i += size
+			continue
+		}
+		if !encoded {
+			encoded = true
+			b.Grow(len(s) + 2*size)
+			b.WriteString(s[:i])
+		}
+		for j := i; j < i+size; j++ {
+			b.WriteByte('%')
+			b.WriteByte(upperHexDigits[s[j]>>4])
+			b.WriteByte(upperHexDigits[s[j]&0x0F])
+		}
+		i += size
+	}
+	if !encoded {
+		return s
+	}
+	return b.String()
+}
+
 // percentEncode is a helper that percent-encodes non-ASCII characters in a string.
 // It is used by Ref.ToURI() to convert an IRI to a URI.
 func percentEncode(s string, b *strings.Builder) {
@@ -81,11 +165,13 @@ func (p *iriParser) readURLCodepointOrEchar(r rune, valid func(rune) bool) error
 		return nil
 	}
 
-	return &kindError{message: "Invalid IRI character", char: r}
+	return p.errorAt(p.input.position()-utf8.RuneLen(r), &kindError{message: "Invalid IRI character", char: r})
 }
 
-// readEchar handles a percent-encoded character (e.g., "%20").
+// readEchar handles a percent-encoded character (e.g., "%20"), whose '%' has
+// already been consumed.
 func (p *iriParser) readEchar() error {
+	start := p.input.position() - 1
 	c1, ok1 := p.input.next()
 	c2, ok2 := p.input.next()
 	if !ok1 || !ok2 || !isASCIIHexDigit(c1) || !isASCIIHexDigit(c2) {
@@ -96,7 +182,16 @@ func (p *iriParser) readEchar() error {
 		if ok2 {
 			details += string(c2)
 		}
-		return &kindError{message: "Invalid IRI percent encoding", details: details}
+		return p.errorAt(start, &kindError{message: "Invalid IRI percent encoding", details: details})
+	}
+	if p.canonicalize {
+		// The octet is decoded if it is unreserved, and written with uppercase
+		// digits otherwise, as Normalize does.
+		if octet := rune(hexDigitValue(c1)<<4 | hexDigitValue(c2)); isUnreserved(octet) {
+			p.output.writeRune(octet)
+			return nil
+		}
+		c1, c2 = unicode.ToUpper(c1), unicode.ToUpper(c2)
 	}
 	p.output.writeRune('%')
 	p.output.writeRune(c1)
@@ -104,6 +199,11 @@ func (p *iriParser) readEchar() error {
 	return nil
 }
 
+// hexDigitValue returns the value of an ASCII hexadecimal digit.
+func hexDigitValue(c rune) int {
+	return strings.IndexRune(upperHexDigits, unicode.ToUpper(c))
+}
+
 // normalizePercentEncoding decodes any percent-encoded octet that corresponds to an
 // unreserved character, as per RFC 3986 Section 6.2.2.2.
 func normalizePercentEncoding(s string) string {
@@ -129,6 +229,89 @@ func normalizePercentEncoding(s string) string {
 	return b.String()
 }
 
+// uppercasePercentEncoding rewrites every percent-encoded octet of a string
+// with uppercase hexadecimal digits (e.g., "%2f" becomes "%2F"), as per
+// RFC 3986, Section 6.2.2.1. Nothing is decoded, and a '%' that does not start
+// a valid escape is left untouched. Since the length of the string does not
+// change, component positions computed on the input remain valid.
+func uppercasePercentEncoding(s string) string {
+	var b []byte
+	for i := 0; i+2 < len(s); i++ {
+		if s[i] != '%' || !isASCIIHexDigit(rune(s[i+1])) || !isASCIIHexDigit(rune(s[i+2])) {
+			continue
+		}
+		for j := i + 1; j <= i+2; j++ {
+			if s[j] >= 'a' && s[j] <= 'f' {
+				if b == nil {
+					b = []byte(s)
+				}
+				b[j] = s[j] - 'a' + 'A'
+			}
+		}
+		i += 2
+	}
+	if b == nil {
+		return s
+	}
+	return string(b)
+}
+
+// percentDecode decodes every percent-encoded octet in a string. Unlike
+// normalizePercentEncoding, it decodes all octets regardless of whether they
+// represent reserved characters. It returns an error if a '%' is not followed
+// by two hexadecimal digits.
+func percentDecode(s string) (string, error) {
+	if !strings.Contains(s, "%") {
+		return s, nil
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+2 >= len(s) || !isASCIIHexDigit(rune(s[i+1])) || !isASCIIHexDigit(rune(s[i+2])) {
+			end := min(i+3, len(s))
+			return "", &kindError{message: "Invalid IRI percent encoding", details: s[i:end]}
+		}
+		decoded, _ := hex.DecodeString(s[i+1 : i+3])
+		b.WriteByte(decoded[0])
+		i += 2
+	}
+	return b.String(), nil
+}
+
+// writeDecodedOctets writes a run of percent-encoded octets, given both in
+// encoded and decoded form, deciding for each character whether it can be
+// decoded when converting a URI to an IRI (RFC 3987, Section 3.2). A non-ASCII
+// character is decoded if it is valid UTF-8 and allowed in IRIs. An ASCII
+// character is only decoded if it is unreserved, as decoding a reserved
+// delimiter like "%2F" would change the structure of the reference. All other
+// octets are written in their original percent-encoded form.
+func writeDecodedOctets(b *strings.Builder, encoded string, decoded []byte) {
+	const encodedLen = len("%XX")
+	for i := 0; i < len(decoded); {
+		size := 1
+		if decoded[i] < utf8.RuneSelf {
+			if isUnreserved(rune(decoded[i])) {
+				b.WriteByte(decoded[i])
+				i++
+				continue
+			}
+		} else {
+			_, size = utf8.DecodeRune(decoded[i:])
+			if validateDecodedBytes(decoded[i : i+size]) {
+				b.Write(decoded[i : i+size])
+				i += size
+				continue
+			}
+		}
+		b.WriteString(encoded[i*encodedLen : (i+size)*encodedLen])
+		i += size
+	}
+}
+
 // validateDecodedBytes checks if a byte slice is valid UTF-8 and contains only allowed characters.
 // Per RFC 3987, Section 4.1, bidi formatting characters are forbidden.
 func validateDecodedBytes(decodedBytes []byte) bool {
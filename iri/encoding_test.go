@@ -18,6 +18,7 @@ limitations under the License.
 package iri
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -93,6 +94,121 @@ func TestValidateDecodedBytes(t *testing.T) {
 	}
 }
 
+// TestEncodePathSegment tests the percent-encoding of IRI path segments, which
+// keeps Unicode characters allowed by RFC 3987 but escapes the others.
+func TestEncodePathSegment(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Nothing to encode", input: "abc-._~!$&'()*+,;=:@", expected: "abc-._~!$&'()*+,;=:@"},
+		{name: "Unicode kept", input: "résumé日本", expected: "résumé日本"},
+		{name: "Delimiters", input: "a/b?c#d", expected: "a%2Fb%3Fc%23d"},
+		{name: "Percent sign", input: "100%", expected: "100%25"},
+		{name: "Space and controls", input: "a b\t\n\x7f", expected: "a%20b%09%0A%7F"},
+		{name: "C1 control", input: "\u0085", expected: "%C2%85"},
+		{name: "Bidi formatting character", input: "a\u200eb", expected: "a%E2%80%8Eb"},
+		{name: "Invalid UTF-8", input: "a\xffb", expected: "a%FFb"},
+		{name: "Empty", input: "", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := EncodePathSegment(tc.input)
+			if got != tc.expected {
+				t.Errorf("EncodePathSegment(%q) = %q; want %q", tc.input, got, tc.expected)
+			}
+			if _, err := ParseRef("http://example.org/" + got); err != nil {
+				t.Errorf("the encoded segment %q is not valid in an IRI: %v", got, err)
+			}
+			if decoded, err := Decode(got); err != nil || decoded != tc.input {
+				t.Errorf("Decode(%q) = (%q, %v); want %q", got, decoded, err, tc.input)
+			}
+		})
+	}
+}
+
+// TestEncodeQueryComponent tests the percent-encoding of query keys and
+// values, checking that they round-trip through Ref.QueryParams.
+func TestEncodeQueryComponent(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Nothing to encode", input: "a/b?c:d@e", expected: "a/b?c:d@e"},
+		{name: "Unicode kept", input: "café", expected: "café"},
+		{name: "Private use kept", input: "\ue000", expected: "\ue000"},
+		{name: "Pair delimiters", input: "a&b=c+d", expected: "a%26b%3Dc%2Bd"},
+		{name: "Fragment delimiter", input: "a#b", expected: "a%23b"},
+		{name: "Space and control", input: "a b\x00", expected: "a%20b%00"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := EncodeQueryComponent(tc.input)
+			if got != tc.expected {
+				t.Errorf("EncodeQueryComponent(%q) = %q; want %q", tc.input, got, tc.expected)
+			}
+			ref, err := ParseRef("http://example.org/?k=" + got)
+			if err != nil {
+				t.Fatalf("the encoded value %q is not valid in an IRI: %v", got, err)
+			}
+			params, err := ref.QueryParams()
+			if err != nil || params.Get("k") != tc.input {
+				t.Errorf("QueryParams() value = (%q, %v); want %q", params.Get("k"), err, tc.input)
+			}
+		})
+	}
+}
+
+// TestDecode tests the public percent-decoding function.
+func TestDecode(t *testing.T) {
+	got, err := Decode("caf%C3%A9+%2F")
+	if err != nil || got != "café+/" {
+		t.Errorf("Decode() = (%q, %v); want %q", got, err, "café+/")
+	}
+
+	_, err = Decode("a%G0")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("Decode() error = %v; want a *ParseError", err)
+	}
+}
+
+// TestWriteDecodedOctets tests the per-character decision taken when decoding
+// percent-encoded octets of a URI, as per RFC 3987, Section 3.2.
+func TestWriteDecodedOctets(t *testing.T) {
+	testCases := []struct {
+		name     string
+		encoded  string
+		expected string
+	}{
+		{name: "UTF-8 sequence", encoded: "%C3%A9", expected: "é"},
+		{name: "Unreserved ASCII", encoded: "%41%7E", expected: "A~"},
+		{name: "Reserved delimiters", encoded: "%2F%3F%23%3A", expected: "%2F%3F%23%3A"},
+		{name: "Disallowed ASCII", encoded: "%20%25", expected: "%20%25"},
+		{name: "Delimiter between UTF-8 sequences", encoded: "%C3%A9%2F%C3%A9", expected: "é%2Fé"},
+		{name: "Invalid UTF-8 byte", encoded: "%E9%41", expected: "%E9A"},
+		{name: "Forbidden bidi character", encoded: "%E2%80%AE%C3%A9", expected: "%E2%80%AEé"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decoded, err := percentDecode(tc.encoded)
+			if err != nil {
+				t.Fatalf("percentDecode(%q) failed: %v", tc.encoded, err)
+			}
+			var b strings.Builder
+			writeDecodedOctets(&b, tc.encoded, []byte(decoded))
+			if got := b.String(); got != tc.expected {
+				t.Errorf("writeDecodedOctets(%q) = %q; want %q", tc.encoded, got, tc.expected)
+			}
+		})
+	}
+}
+
 // TestNormalizePercentEncoding tests the normalization of percent-encoded octets.
 // RFC Reference: RFC 3986, Section 6.2.2.2. It specifies that any percent-encoded
 // octet corresponding to an unreserved character should be decoded. Unreserved
@@ -175,6 +291,70 @@ func TestNormalizePercentEncoding(t *testing.T) {
 	}
 }
 
+// TestPercentDecode tests the decoding of all percent-encoded octets.
+// RFC Reference: RFC 3986, Section 2.1 defines a percent-encoded octet as
+// "%" HEXDIG HEXDIG.
+func TestPercentDecode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "No percent encoding", input: "abc-123", expected: "abc-123"},
+		{name: "Empty string", input: "", expected: ""},
+		{name: "Decode reserved characters", input: "a%2Fb%3Fc", expected: "a/b?c"},
+		{name: "Decode lowercase hex", input: "a%2fb", expected: "a/b"},
+		{name: "Decode UTF-8 sequence", input: "r%C3%A9sum%C3%A9", expected: "résumé"},
+		{name: "Invalid encoding - short", input: "a%2", wantErr: true},
+		{name: "Invalid encoding - non-hex", input: "a%2Gb", wantErr: true},
+		{name: "Invalid encoding - trailing percent", input: "a%", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := percentDecode(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("percentDecode(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+			if result != tc.expected {
+				t.Errorf("percentDecode(%q) = %q; want %q", tc.input, result, tc.expected)
+			}
+		})
+	}
+}
+
+// TestUppercasePercentEncoding tests the case normalization of percent-encoded
+// octets. RFC Reference: RFC 3986, Section 6.2.2.1 states that the hexadecimal
+// digits within a percent-encoding triplet should be uppercase.
+func TestUppercasePercentEncoding(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "No percent encoding", input: "abc-def", expected: "abc-def"},
+		{name: "Empty string", input: "", expected: ""},
+		{name: "Already uppercase", input: "a%2Fb%C3%A9", expected: "a%2Fb%C3%A9"},
+		{name: "Lowercase hex", input: "a%2fb%c3%a9", expected: "a%2Fb%C3%A9"},
+		{name: "Mixed-case hex", input: "%3a%Ab%aB", expected: "%3A%AB%AB"},
+		{name: "Unreserved stays encoded", input: "%7euser", expected: "%7Euser"},
+		{name: "Escape at end of string", input: "ab%ff", expected: "ab%FF"},
+		{name: "Invalid escape untouched", input: "a%zzb%2", expected: "a%zzb%2"},
+		{name: "Percent before valid escape", input: "%%2f", expected: "%%2F"},
+		{name: "Letters outside escapes untouched", input: "abcdef%0a", expected: "abcdef%0A"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := uppercasePercentEncoding(tc.input)
+			if result != tc.expected {
+				t.Errorf("uppercasePercentEncoding(%q) = %q; want %q", tc.input, result, tc.expected)
+			}
+		})
+	}
+}
+
 // TestPercentEncode tests the percent-encoding of non-ASCII characters.
 // RFC Reference: RFC 3987, Section 3.1, Step 2 defines the mapping from IRI
 // characters to URI octets via UTF-8, then percent-encoding. RFC 3986, Section 2.5
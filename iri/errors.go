@@ -19,6 +19,8 @@ package iri
 import (
 	"errors"
 	"fmt"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 var (
@@ -34,15 +36,81 @@ var (
 	errPathStartingWithSlashes = &kindError{
 		message: "An IRI path is not allowed to start with // if there is no authority",
 	}
+	// errPathNotAbsoluteWithAuthority is returned when an IRI is assembled from
+	// components that include an authority and a non-empty path that does not
+	// start with "/". RFC 3986, Section 3.3 requires such a path to be either
+	// empty or absolute, otherwise it would be merged into the authority.
+	errPathNotAbsoluteWithAuthority = &kindError{
+		message: "An IRI path must be empty or start with / if there is an authority",
+	}
 )
 
 // newParseError creates a new ParseError, wrapping the original error.
-// It returns nil if the input error is nil.
+// It returns nil if the input error is nil. The offset recorded by the parser,
+// if any, is moved to ParseError.Offset.
 func newParseError(err error) *ParseError {
 	if err == nil {
 		return nil
 	}
-	return &ParseError{Message: err.Error(), Err: errors.Unwrap(err)}
+	offset := -1
+	var oe *offsetError
+	if errors.As(err, &oe) {
+		// The parser only returns an offsetError as the outermost error.
+		offset = oe.offset
+		err = oe.err
+	}
+	return &ParseError{Message: err.Error(), Err: errors.Unwrap(err), Offset: offset}
+}
+
+// newNormalizedParseError is like newParseError for an error found while
+// parsing normalized, the NFC form of input. The offset is mapped back to
+// input, so that it is always relative to the string given by the caller.
+func newNormalizedParseError(err error, input, normalized string) *ParseError {
+	pe := newParseError(err)
+	if pe != nil && pe.Offset >= 0 && input != normalized {
+		pe.Offset = nfcInputOffset(input, pe.Offset)
+	}
+	return pe
+}
+
+// nfcInputOffset maps an offset in the NFC form of input to the matching
+// offset in input. An offset within a character whose normalization changed
+// it, e.g., in "é" composed from "e" and a combining accent, is moved to the
+// start of that character.
+func nfcInputOffset(input string, offset int) int {
+	var it norm.Iter
+	it.InitString(norm.NFC, input)
+	normalizedPos := 0
+	for !it.Done() {
+		start := it.Pos()
+		segment := it.Next()
+		if offset < normalizedPos+len(segment) {
+			if string(segment) == input[start:it.Pos()] {
+				return start + offset - normalizedPos
+			}
+			return start
+		}
+		normalizedPos += len(segment)
+	}
+	return len(input)
+}
+
+// offsetError records the byte offset in the input at which the parser
+// failed. It is transparent otherwise: it has the message of the wrapped
+// error and unwraps to it.
+type offsetError struct {
+	err    error
+	offset int
+}
+
+// Error returns the message of the wrapped error.
+func (e *offsetError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *offsetError) Unwrap() error {
+	return e.err
 }
 
 // kindError is a specialized error type used by the parser to provide
@@ -64,3 +132,37 @@ func (e *kindError) Error() string {
 	}
 	return msg
 }
+
+// relativizeError is returned when relativization fails for a reason other
+// than dot-segments in the target path. It unwraps to ErrIriRelativize so that
+// callers can test for any relativization failure with errors.Is.
+type relativizeError struct {
+	reason string
+}
+
+// Error returns the reason why the relativization failed.
+func (e *relativizeError) Error() string {
+	return "it is not possible to make this IRI relative: " + e.reason
+}
+
+// Unwrap returns ErrIriRelativize.
+func (e *relativizeError) Unwrap() error {
+	return ErrIriRelativize
+}
+
+// filePathError is returned when a conversion between a "file" IRI and a
+// filesystem path fails. It unwraps to ErrFilePath so that callers can test
+// for any such failure with errors.Is.
+type filePathError struct {
+	reason string
+}
+
+// Error returns the reason why the conversion failed.
+func (e *filePathError) Error() string {
+	return ErrFilePath.Error() + ": " + e.reason
+}
+
+// Unwrap returns ErrFilePath.
+func (e *filePathError) Unwrap() error {
+	return ErrFilePath
+}
@@ -98,6 +98,24 @@ func TestNewParseError(t *testing.T) {
 		if parseErr.Err != nil {
 			t.Errorf("ParseError.Err should be nil for a simple error, but got %v", parseErr.Err)
 		}
+		if parseErr.Offset != -1 {
+			t.Errorf("ParseError.Offset = %d, want -1 for an error without offset", parseErr.Offset)
+		}
+	})
+
+	t.Run("Offset Error", func(t *testing.T) {
+		originalErr := &offsetError{err: errNoScheme, offset: 3}
+		parseErr := newParseError(originalErr)
+
+		if parseErr.Message != errNoScheme.Error() {
+			t.Errorf("ParseError.Message = %q, want %q", parseErr.Message, errNoScheme.Error())
+		}
+		if parseErr.Err != nil {
+			t.Errorf("ParseError.Err should be nil for an offset error, but got %v", parseErr.Err)
+		}
+		if parseErr.Offset != 3 {
+			t.Errorf("ParseError.Offset = %d, want 3", parseErr.Offset)
+		}
 	})
 
 	t.Run("Wrapped Error", func(t *testing.T) {
@@ -140,4 +158,37 @@ func TestGlobalErrors(t *testing.T) {
 			t.Errorf("errPathStartingWithSlashes.Error() = %q, want %q", got, expected)
 		}
 	})
+	t.Run("errPathNotAbsoluteWithAuthority", func(t *testing.T) {
+		// RFC 3986, Section 3.3 states: "When authority is present, the path
+		// must either be empty or begin with a slash ('/') character".
+		expected := "An IRI path must be empty or start with / if there is an authority"
+		if got := errPathNotAbsoluteWithAuthority.Error(); got != expected {
+			t.Errorf("errPathNotAbsoluteWithAuthority.Error() = %q, want %q", got, expected)
+		}
+	})
+}
+
+// TestRelativizeError tests that relativizeError reports its reason and
+// unwraps to ErrIriRelativize.
+func TestRelativizeError(t *testing.T) {
+	err := &relativizeError{reason: "the base reference is relative"}
+	expected := "it is not possible to make this IRI relative: the base reference is relative"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+	if !errors.Is(err, ErrIriRelativize) {
+		t.Error("Expected relativizeError to unwrap to ErrIriRelativize")
+	}
+}
+
+// TestFilePathError tests the error returned by file IRI conversions.
+func TestFilePathError(t *testing.T) {
+	err := &filePathError{reason: "the path a is not absolute"}
+	expected := "it is not possible to convert between a file IRI and a path: the path a is not absolute"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+	if !errors.Is(err, ErrFilePath) {
+		t.Error("Expected filePathError to unwrap to ErrFilePath")
+	}
 }
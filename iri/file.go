@@ -0,0 +1,153 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iri
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+)
+
+// ErrFilePath is returned by FilePath and FromFilePath when a "file" IRI
+// cannot be converted to a filesystem path, or the other way around. The
+// returned error carries the reason and unwraps to ErrFilePath.
+var ErrFilePath = errors.New("it is not possible to convert between a file IRI and a path")
+
+// FilePath converts a "file" IRI, as defined by RFC 8089, into a path for the
+// current operating system. Both the "file:///path" and "file:/path" forms are
+// accepted, as well as an explicit "localhost" host. The path segments are
+// percent-decoded.
+//
+// On Windows, a leading drive letter is recognized ("file:///C:/x" becomes
+// "C:\x") and an IRI with a remote host is converted to a UNC path
+// ("file://host/share/x" becomes "\\host\share\x"). On other systems, a
+// remote host cannot be represented and an error is returned.
+func (r *Ref) FilePath() (string, error) {
+	return r.filePath(runtime.GOOS == "windows")
+}
+
+// FromFilePath converts an absolute path for the current operating system
+// into a "file" IRI. Characters that are not allowed in an IRI path, such as
+// spaces or '%', are percent-encoded. It is the inverse of FilePath.
+func FromFilePath(p string) (*Iri, error) {
+	return fromFilePath(p, runtime.GOOS == "windows")
+}
+
+// filePath implements FilePath, with the target system given explicitly so
+// that both conventions can be exercised on any platform.
+func (r *Ref) filePath(windows bool) (string, error) {
+	scheme, ok := r.Scheme()
+	if !ok || !strings.EqualFold(scheme, "file") {
+		return "", &filePathError{reason: "the IRI does not use the file scheme"}
+	}
+	if _, ok = r.UserInfo(); ok {
+		return "", &filePathError{reason: "a file IRI cannot have a userinfo"}
+	}
+	if _, ok = r.Port(); ok {
+		return "", &filePathError{reason: "a file IRI cannot have a port"}
+	}
+	host, _ := r.Host()
+	if strings.EqualFold(host, "localhost") {
+		host = ""
+	}
+	if host != "" && !windows {
+		return "", &filePathError{reason: "the remote host " + host + " cannot be represented as a local path"}
+	}
+
+	path := r.Path()
+	if path == "" && r.HasAuthority() {
+		path = "/"
+	}
+	if !strings.HasPrefix(path, "/") {
+		return "", &filePathError{reason: "the path of the IRI is not absolute"}
+	}
+
+	segments := strings.Split(path[1:], "/")
+	for i, segment := range segments {
+		decoded, err := percentDecode(segment)
+		if err != nil {
+			return "", newParseError(err)
+		}
+		if strings.ContainsAny(decoded, "/\x00") || (windows && strings.ContainsRune(decoded, '\\')) {
+			return "", &filePathError{reason: "the path segment " + segment + " contains a separator or a NUL byte"}
+		}
+		segments[i] = decoded
+	}
+
+	if !windows {
+		return "/" + strings.Join(segments, "/"), nil
+	}
+	if host != "" {
+		return `\\` + host + `\` + strings.Join(segments, `\`), nil
+	}
+	if isDriveLetter(segments[0]) {
+		if len(segments) == 1 {
+			// A bare drive ("file:///C:") denotes its root directory.
+			return segments[0] + `\`, nil
+		}
+		return strings.Join(segments, `\`), nil
+	}
+	return `\` + strings.Join(segments, `\`), nil
+}
+
+// fromFilePath implements FromFilePath, with the source system given
+// explicitly so that both conventions can be exercised on any platform.
+func fromFilePath(p string, windows bool) (*Iri, error) {
+	var host, path string
+	switch {
+	case windows && (strings.HasPrefix(p, `\\`) || strings.HasPrefix(p, "//")):
+		// A UNC path: "\\host\share\x".
+		var found bool
+		host, path, found = strings.Cut(strings.ReplaceAll(p[2:], `\`, "/"), "/")
+		if host == "" || !found {
+			return nil, &filePathError{reason: "the UNC path " + p + " has no host or share"}
+		}
+		path = "/" + path
+	case windows:
+		path = strings.ReplaceAll(p, `\`, "/")
+		drive, _, _ := strings.Cut(path, "/")
+		if !isDriveLetter(drive) {
+			return nil, &filePathError{reason: "the path " + p + " is not absolute"}
+		}
+		path = "/" + path
+	default:
+		if !strings.HasPrefix(p, "/") {
+			return nil, &filePathError{reason: "the path " + p + " is not absolute"}
+		}
+		path = p
+	}
+
+	var b strings.Builder
+	b.Grow(len("file://") + len(host) + len(path))
+	b.WriteString("file://")
+	b.WriteString(host)
+	output := &stringOutputBuffer{builder: &b}
+	for _, r := range path {
+		if r == '/' || isIUnreservedOrSubDelims(r) || r == ':' || r == '@' {
+			b.WriteRune(r)
+		} else {
+			percentEncodeRune(r, output)
+		}
+	}
+	return ParseIri(b.String())
+}
+
+// isDriveLetter reports whether a path segment is a Windows drive letter
+// (e.g., "C:").
+func isDriveLetter(segment string) bool {
+	return len(segment) == len("C:") && isASCIILetter(rune(segment[0])) && segment[1] == ':'
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//nolint:testpackage // This is a white-box test file for an internal package. It needs to be in the same package to test unexported functions.
+package iri
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestRef_FilePath tests the conversion of file IRIs (RFC 8089) into paths,
+// following both the POSIX and the Windows conventions.
+func TestRef_FilePath(t *testing.T) {
+	testCases := []struct {
+		name    string
+		iri     string
+		windows bool
+		want    string
+	}{
+		{"POSIX empty authority", "file:///home/user/a.txt", false, "/home/user/a.txt"},
+		{"POSIX no authority", "file:/home/user/a.txt", false, "/home/user/a.txt"},
+		{"POSIX localhost", "file://LocalHost/etc/hosts", false, "/etc/hosts"},
+		{"POSIX percent-decoding", "file:///tmp/a%20b/%C3%A9%25", false, "/tmp/a b/é%"},
+		{"POSIX root", "file:///", false, "/"},
+		{"POSIX root without path", "file://localhost", false, "/"},
+		{"POSIX drive letter is a directory", "file:///C:/x", false, "/C:/x"},
+		{"POSIX uppercase scheme", "FILE:///a", false, "/a"},
+		{"Windows drive letter", "file:///C:/x/y.txt", true, `C:\x\y.txt`},
+		{"Windows drive root", "file:///c:/", true, `c:\`},
+		{"Windows bare drive", "file:///D:", true, `D:\`},
+		{"Windows UNC", "file://server/share/a%20b", true, `\\server\share\a b`},
+		{"Windows localhost", "file://localhost/C:/x", true, `C:\x`},
+		{"Windows rooted path", "file:///x/y", true, `\x\y`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := mustParseRef(t, tc.iri).filePath(tc.windows)
+			if err != nil {
+				t.Fatalf("filePath() returned an unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("filePath() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestRef_FilePath_Invalid tests the IRIs that cannot be converted into paths.
+func TestRef_FilePath_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		iri     string
+		windows bool
+	}{
+		{"Other scheme", "http://example.com/a", false},
+		{"Relative reference", "/a/b", false},
+		{"POSIX remote host", "file://server/share/a", false},
+		{"Userinfo", "file://user@localhost/a", false},
+		{"Port", "file://localhost:80/a", false},
+		{"Rootless path", "file:a/b", false},
+		{"Encoded slash", "file:///a%2Fb", false},
+		{"Encoded NUL", "file:///a%00b", false},
+		{"Windows encoded backslash", "file:///C:/a%5Cb", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := mustParseRef(t, tc.iri).filePath(tc.windows)
+			if !errors.Is(err, ErrFilePath) {
+				t.Errorf("filePath() error = %v, want ErrFilePath", err)
+			}
+		})
+	}
+}
+
+// TestFromFilePath tests the conversion of paths into file IRIs, and that
+// FilePath reverses it.
+func TestFromFilePath(t *testing.T) {
+	testCases := []struct {
+		name    string
+		path    string
+		windows bool
+		want    string
+	}{
+		{"POSIX path", "/home/user/a.txt", false, "file:///home/user/a.txt"},
+		{"POSIX root", "/", false, "file:///"},
+		{"POSIX special characters", "/tmp/a b/50%#1?", false, "file:///tmp/a%20b/50%25%231%3F"},
+		{"POSIX Unicode", "/tmp/résumé", false, "file:///tmp/résumé"},
+		{"POSIX backslash", `/tmp/a\b`, false, "file:///tmp/a%5Cb"},
+		{"Windows drive letter", `C:\x\y.txt`, true, "file:///C:/x/y.txt"},
+		{"Windows drive root", `C:\`, true, "file:///C:/"},
+		{"Windows UNC", `\\server\share\a b`, true, "file://server/share/a%20b"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := fromFilePath(tc.path, tc.windows)
+			if err != nil {
+				t.Fatalf("fromFilePath() returned an unexpected error: %v", err)
+			}
+			if got.String() != tc.want {
+				t.Errorf("fromFilePath() = %q, want %q", got.String(), tc.want)
+			}
+			back, err := got.filePath(tc.windows)
+			if err != nil {
+				t.Fatalf("filePath() returned an unexpected error: %v", err)
+			}
+			if back != tc.path {
+				t.Errorf("filePath() = %q, want the original path %q", back, tc.path)
+			}
+		})
+	}
+}
+
+// TestFromFilePath_Invalid tests the paths that cannot be converted into IRIs.
+func TestFromFilePath_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		path    string
+		windows bool
+	}{
+		{"POSIX relative path", "a/b", false},
+		{"POSIX empty path", "", false},
+		{"Windows relative path", `a\b`, true},
+		{"Windows rooted path without drive", `\a\b`, true},
+		{"Windows UNC without share", `\\server`, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := fromFilePath(tc.path, tc.windows); !errors.Is(err, ErrFilePath) {
+				t.Errorf("fromFilePath() error = %v, want ErrFilePath", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iri
+
+// GobEncode implements the gob.GobEncoder interface. The positions of the
+// components are not part of the encoding: only the IRI reference string is,
+// and GobDecode parses it again.
+func (r *Ref) GobEncode() ([]byte, error) {
+	return r.MarshalText()
+}
+
+// GobDecode implements the gob.GobDecoder interface. Like UnmarshalText, it
+// validates the data with ParseRef, so invalid data results in a *ParseError.
+func (r *Ref) GobDecode(data []byte) error {
+	return r.UnmarshalText(data)
+}
+
+// GobEncode implements the gob.GobEncoder interface, encoding the IRI string.
+func (i *Iri) GobEncode() ([]byte, error) {
+	return i.MarshalText()
+}
+
+// GobDecode implements the gob.GobDecoder interface. Like UnmarshalText, it
+// validates the data with ParseIri, so a relative reference results in a
+// *ParseError.
+func (i *Iri) GobDecode(data []byte) error {
+	return i.UnmarshalText(data)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iri
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+)
+
+// Compile-time checks that Ref and Iri implement the encoding/gob interfaces.
+var (
+	_ gob.GobEncoder = (*Ref)(nil)
+	_ gob.GobDecoder = (*Ref)(nil)
+	_ gob.GobEncoder = (*Iri)(nil)
+	_ gob.GobDecoder = (*Iri)(nil)
+)
+
+// gobRecord holds IRIs in the various forms a gob-encoded value can use.
+type gobRecord struct {
+	Base    Iri
+	Link    *Ref
+	Related []Iri
+}
+
+// TestIri_Gob tests encoding Iri and Ref values with encoding/gob and decoding
+// them back, with working component accessors.
+func TestIri_Gob(t *testing.T) {
+	in := gobRecord{
+		Base:    *mustParseIri(t, "http://user@example.com:8080/a/b?q#f"),
+		Link:    mustParseRef(t, "../c/résumé?x=1"),
+		Related: []Iri{*mustParseIri(t, "urn:isbn:0451450523"), *mustParseIri(t, "http://[::1]/")},
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&in); err != nil {
+		t.Fatalf("Encode() returned an unexpected error: %v", err)
+	}
+
+	var out gobRecord
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode() returned an unexpected error: %v", err)
+	}
+	if out.Base.String() != in.Base.String() || out.Link.String() != in.Link.String() {
+		t.Errorf("Round trip produced %s and %s, want %s and %s",
+			out.Base.String(), out.Link, in.Base.String(), in.Link)
+	}
+	if len(out.Related) != len(in.Related) {
+		t.Fatalf("Round trip produced %d related IRIs, want %d", len(out.Related), len(in.Related))
+	}
+	for i := range in.Related {
+		if out.Related[i].String() != in.Related[i].String() {
+			t.Errorf("Related[%d] = %s, want %s", i, out.Related[i].String(), in.Related[i].String())
+		}
+	}
+
+	if host, _ := out.Base.Host(); host != "example.com" {
+		t.Errorf("Host() after decoding = %q, want %q", host, "example.com")
+	}
+	if port, _ := out.Base.Port(); port != "8080" {
+		t.Errorf("Port() after decoding = %q, want %q", port, "8080")
+	}
+	if query, _ := out.Link.Query(); query != "x=1" {
+		t.Errorf("Query() after decoding = %q, want %q", query, "x=1")
+	}
+	if host, _ := out.Related[1].Host(); host != "[::1]" {
+		t.Errorf("Host() after decoding = %q, want %q", host, "[::1]")
+	}
+}
+
+// TestIri_GobDecode_Invalid tests that decoding rejects malformed data, and
+// relative references for an Iri.
+func TestIri_GobDecode_Invalid(t *testing.T) {
+	var parseErr *ParseError
+	var ref Ref
+	if err := ref.GobDecode([]byte("http://example.com/[")); !errors.As(err, &parseErr) {
+		t.Errorf("Ref.GobDecode() error = %v, want a *ParseError", err)
+	}
+	var iri Iri
+	if err := iri.GobDecode([]byte("/a/b")); !errors.As(err, &parseErr) {
+		t.Errorf("Iri.GobDecode() error = %v, want a *ParseError", err)
+	}
+	if err := ref.GobDecode([]byte("/a/b")); err != nil {
+		t.Errorf("Ref.GobDecode() returned an unexpected error: %v", err)
+	}
+}
@@ -65,8 +65,8 @@ func (p *parserInput) asStr() string {
 	return p.originalString[p.position():]
 }
 
-// reset re-initializes the input with a new string.
+// reset re-initializes the input with a new string, reusing the underlying reader.
 func (p *parserInput) reset(s string) {
 	p.originalString = s
-	p.reader = strings.NewReader(s)
+	p.reader.Reset(s)
 }
@@ -28,7 +28,7 @@ limitations under the License.
 //   - Reference resolution (`Resolve`) to compute an absolute IRI from a base and a relative reference.
 //   - Relativization (`Relativize`) to compute a relative reference between two absolute IRIs.
 //   - Zero-allocation resolution variants (`ResolveTo`) for performance-critical applications.
-//   - Support for JSON marshalling and unmarshalling.
+//   - Support for JSON and text (encoding.TextMarshaler) marshalling and unmarshalling.
 package iri
 
 import (
@@ -36,7 +36,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"strings"
+	"sync/atomic"
+	"unsafe"
 
 	// TODO: At some point implement my own IDNA2003 module (RFC 3490).
 	"golang.org/x/net/idna"
@@ -46,9 +50,15 @@ import (
 
 // ParseError is the error type returned by parsing functions in this package.
 // It contains a descriptive message and may wrap a more specific internal error.
+// Offset is the byte offset in the input at which parsing failed (e.g., 10,
+// the space, for "http://exa mple.com"), or -1 if the failure cannot be tied
+// to a single position, as for an error found once the IRI is resolved. It is
+// relative to the string given by the caller, even when that string is
+// normalized to NFC before being parsed.
 type ParseError struct {
 	Message string
 	Err     error
+	Offset  int
 }
 
 // Error returns the string representation of the parse error.
@@ -66,6 +76,17 @@ func (e *ParseError) Unwrap() error {
 // ("." or ".."). Such paths must be normalized before relativization.
 var ErrIriRelativize = errors.New("it is not possible to make this IRI relative because it contains '/..' or '/.'")
 
+// ErrPathAboveRoot is returned, wrapped in a *ParseError, by ResolveStrict
+// when a relative reference uses ".." segments to go above the root of the
+// path, as in "../../../g" against "http://a/b". It can be detected with
+// errors.Is.
+var ErrPathAboveRoot = errors.New("the relative IRI goes above the root of the path")
+
+// ErrTooLong is returned, wrapped in a *ParseError, by the functions enforcing
+// a maximum length when an IRI, before or after resolution, is longer than
+// allowed. It can be detected with errors.Is.
+var ErrTooLong = errors.New("the IRI exceeds the maximum length")
+
 // Ref represents an IRI reference, which can be either absolute or relative.
 // It is an immutable type; methods that modify the IRI, like Resolve, return a new Ref.
 // The internal `iri` string is stored exactly as provided to the parsing function.
@@ -73,7 +94,32 @@ var ErrIriRelativize = errors.New("it is not possible to make this IRI relative
 // or the `Normalize()` method.
 type Ref struct {
 	iri       string
-	positions Positions
+	positions DetailedPositions
+	// normalized caches the *Ref returned by Normalize. It is shared, through
+	// a pointer, by all the copies of the Ref, so that copying a Ref (e.g., into
+	// an Iri) never races with a concurrent Normalize. It is nil for a Ref
+	// that was not built by this package, which is then not cached.
+	normalized *normalizeCache
+}
+
+// normalizeCache holds the result of Normalize for a Ref and its copies.
+type normalizeCache struct {
+	ref atomic.Pointer[Ref]
+}
+
+// cachedRef allocates a Ref together with its normalization cache, so that
+// the cache does not cost an extra allocation.
+type cachedRef struct {
+	ref   Ref
+	cache normalizeCache
+}
+
+// newRef returns a Ref for the given IRI reference and positions, with an
+// empty normalization cache.
+func newRef(iri string, positions DetailedPositions) *Ref {
+	c := &cachedRef{ref: Ref{iri: iri, positions: positions}}
+	c.ref.normalized = &c.cache
+	return &c.ref
 }
 
 // ParseRef parses and validates a string as an IRI reference.
@@ -85,12 +131,130 @@ type Ref struct {
 // For applications that require canonical equivalence for comparison or storage,
 // use `ParseNormalizedRef` instead.
 func ParseRef(s string) (*Ref, error) {
-	pos, err := run(s, nil, false, &voidOutputBuffer{})
+	return ParseWithOptions(s, Options{})
+}
+
+// ParseRefWithMaxLength is like ParseRef but rejects inputs longer than maxLen
+// bytes with an error wrapping ErrTooLong, before doing any parsing work. It is
+// meant to protect servers from pathological inputs. A maxLen of zero or less
+// disables the limit.
+func ParseRefWithMaxLength(s string, maxLen int) (*Ref, error) {
+	pos, err := runWithLimits(s, nil, false, &voidOutputBuffer{}, maxLen)
+	if err != nil {
+		return nil, newParseError(err)
+	}
+
+	return newRef(s, pos), nil
+}
+
+// Options configures ParseWithOptions. The zero value parses any IRI
+// reference as ParseRef does.
+type Options struct {
+	// RequireScheme rejects relative references, as ParseIri does.
+	RequireScheme bool
+	// Normalize applies NFC normalization to the input before parsing it, as
+	// ParseNormalizedRef does.
+	Normalize bool
+	// Unchecked skips validation entirely and only locates the components.
+	// It must only be used for inputs already known to be valid.
+	Unchecked bool
+	// AllowBidiMix disables the bidirectional text rules of RFC 3987,
+	// Section 4.2, which are only recommendations, so that a userinfo or host
+	// labels mixing left-to-right and right-to-left characters are accepted.
+	// As with ParseRef, the other components are only checked against these
+	// rules when a reference is resolved. Characters and percent-encodings
+	// are still validated.
+	AllowBidiMix bool
+	// Canonicalize applies the normalization of Ref.Normalize while parsing,
+	// in a single pass, instead of parsing the reference and normalizing the
+	// result: the scheme and host are lowercased and the unreserved
+	// percent-encodings decoded as they are written, the dot-segments are
+	// removed at the end of the path, and the default port is dropped. The
+	// input is first normalized to NFC, as with Normalize. The result is the
+	// same as ParseRef followed by Normalize; in particular, as with ParseRef,
+	// only the host is checked against the bidi rules.
+	Canonicalize bool
+}
+
+// ParseWithOptions parses an IRI reference with the behaviors selected in
+// opts, which can be combined, e.g., to get a normalized absolute IRI. With
+// the zero Options, it is the same as ParseRef, and with only RequireScheme
+// set, as ParseIri. When RequireScheme is set, the result can be turned into
+// an Iri with NewIriFromRef, which cannot fail.
+func ParseWithOptions(input string, opts Options) (*Ref, error) {
+	s := input
+	if opts.Normalize || opts.Canonicalize {
+		s = norm.NFC.String(s)
+	}
+	var output outputBuffer = &voidOutputBuffer{}
+	if opts.Canonicalize {
+		// The canonical form differs from the input, so it must be recorded.
+		output = &stringOutputBuffer{builder: &strings.Builder{}}
+	}
+	pos, err := runWithOptions(s, opts, output)
+	if err != nil {
+		return nil, newNormalizedParseError(err, input, s)
+	}
+
+	ref := newRef(s, pos)
+	if opts.Canonicalize {
+		ref.iri = output.string()
+	}
+	if opts.RequireScheme && !ref.IsAbsolute() {
+		return nil, newParseError(errNoScheme)
+	}
+	return ref, nil
+}
+
+// ParseRefBytes is like ParseRef but takes a byte slice, as typically read
+// from a socket or a file. The input is validated in place, without first
+// converting it to a string; the bytes are only copied into the returned Ref
+// once validation succeeds, so invalid input costs no copy at all. The
+// returned Ref does not retain b, which may be reused by the caller.
+func ParseRefBytes(b []byte) (*Ref, error) {
+	// The view is only read during validation and never retained.
+	view := unsafe.String(unsafe.SliceData(b), len(b)) //nolint:gosec // b is not mutated while the view is alive.
+	pos, err := runDetailed(view, nil, false, &voidOutputBuffer{})
 	if err != nil {
 		return nil, newParseError(err)
 	}
 
-	return &Ref{iri: s, positions: pos}, nil
+	return newRef(string(b), pos), nil
+}
+
+// Validate reads a single IRI reference from r and checks that it is valid,
+// as ParseRef would, without building a Ref. The whole content of the reader
+// is taken as the reference, so a trailing newline makes it invalid; to
+// validate a list with one reference per line, split it first (e.g., with
+// bufio.Scanner) and call Validate or ParseRef on each line.
+//
+// Validate does not support resolution against a base: relative references
+// are only checked for well-formedness. An error returned by r is passed
+// through as-is, while an invalid reference yields a *ParseError.
+func Validate(r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if _, err = run(string(b), nil, false, &voidOutputBuffer{}); err != nil {
+		return newParseError(err)
+	}
+	return nil
+}
+
+// IsValidIRIReference reports whether s is a valid IRI reference, as ParseRef
+// would accept it. It does not build a Ref, so the only allocations are those
+// of the parser state.
+func IsValidIRIReference(s string) bool {
+	_, err := run(s, nil, false, &voidOutputBuffer{})
+	return err == nil
+}
+
+// IsValidIRI reports whether s is a valid absolute IRI, as ParseIri would
+// accept it. Like IsValidIRIReference, it does not build a Ref.
+func IsValidIRI(s string) bool {
+	pos, err := run(s, nil, false, &voidOutputBuffer{})
+	return err == nil && pos.SchemeEnd != 0
 }
 
 // ParseNormalizedRef provides the previous behavior of ParseRef for users
@@ -105,12 +269,12 @@ func ParseRef(s string) (*Ref, error) {
 func ParseNormalizedRef(s string) (*Ref, error) {
 	normalizedIRI := norm.NFC.String(s)
 
-	pos, err := run(normalizedIRI, nil, false, &voidOutputBuffer{})
+	pos, err := runDetailed(normalizedIRI, nil, false, &voidOutputBuffer{})
 	if err != nil {
-		return nil, newParseError(err)
+		return nil, newNormalizedParseError(err, s, normalizedIRI)
 	}
 
-	return &Ref{iri: normalizedIRI, positions: pos}, nil
+	return newRef(normalizedIRI, pos), nil
 }
 
 // ParseURIToRef converts a URI string into an IRI reference by decoding
@@ -121,7 +285,14 @@ func ParseNormalizedRef(s string) (*Ref, error) {
 // string to ensure it forms a syntactically correct IRI reference. Any
 // percent-encoded octets that do not form a valid UTF-8 sequence or that
 // represent characters not permitted in IRIs (such as bidi control characters)
-// are left in their percent-encoded form.
+// are left in their percent-encoded form. ASCII characters are only decoded if
+// they are unreserved: reserved delimiters such as "%2F", "%3F", "%23" or
+// "%3A" always stay encoded, since decoding them would change the structure of
+// the reference (e.g., "a%2Fb" is a single path segment, unlike "a/b").
+//
+// Host labels in punycode (starting with "xn--") are converted back to
+// Unicode with IDNA ToUnicode, reversing the ToASCII conversion applied by
+// ToURI. Labels that cannot be decoded are left unchanged.
 func ParseURIToRef(s string) (*Ref, error) {
 	var builder strings.Builder
 	builder.Grow(len(s))
@@ -155,18 +326,51 @@ func ParseURIToRef(s string) (*Ref, error) {
 			continue
 		}
 
-		if validateDecodedBytes(decodedBytes) {
-			builder.Write(decodedBytes)
-		} else {
-			// Not valid UTF-8 or contains forbidden characters, so keep original encoding.
-			builder.WriteString(s[start:i])
-		}
+		writeDecodedOctets(&builder, s[start:i], decodedBytes)
 	}
 
 	// The decoded string must be re-parsed to ensure it is a valid IRI.
 	// ParseNormalizedRef is used here because URI-to-IRI conversion
 	// implies a canonical representation is desired.
-	return ParseNormalizedRef(builder.String())
+	ref, err := ParseNormalizedRef(builder.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert punycode host labels back to Unicode, reversing the IDNA
+	// ToASCII operation applied by ToURI.
+	host, _ := ref.Host()
+	unicodeHost := decodePunycodeHost(host)
+	if unicodeHost == host {
+		return ref, nil
+	}
+	decoded := ref.iri[:ref.positions.UserInfoEnd] + unicodeHost + ref.iri[ref.positions.HostEnd:]
+	if unicodeRef, unicodeErr := ParseNormalizedRef(decoded); unicodeErr == nil {
+		return unicodeRef, nil
+	}
+	// The Unicode host is not allowed in an IRI (e.g., it breaks the bidi
+	// rules), so the punycode form is kept.
+	return ref, nil
+}
+
+// decodePunycodeHost applies IDNA ToUnicode to every label of a host that
+// starts with the ACE prefix "xn--". Labels that fail to decode, as well as
+// IP literals, are left as-is.
+func decodePunycodeHost(host string) string {
+	if strings.HasPrefix(host, "[") || !strings.Contains(strings.ToLower(host), "xn--") {
+		return host
+	}
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		if len(label) < len("xn--") || !strings.EqualFold(label[:len("xn--")], "xn--") {
+			continue
+		}
+		// The ACE prefix and the punycode itself are case-insensitive.
+		if unicodeLabel, err := idna.ToUnicode(strings.ToLower(label)); err == nil {
+			labels[i] = unicodeLabel
+		}
+	}
+	return strings.Join(labels, ".")
 }
 
 // Resolve resolves a relative IRI reference against the current Ref (which acts as the base IRI).
@@ -174,11 +378,100 @@ func ParseURIToRef(s string) (*Ref, error) {
 func (r *Ref) Resolve(relativeIRI string) (*Ref, error) {
 	builder := &strings.Builder{}
 	builder.Grow(len(r.iri) + len(relativeIRI)) // Pre-allocate for efficiency.
-	pos, err := r.ResolveTo(relativeIRI, builder)
+	pos, err := r.resolveTo(relativeIRI, builder)
 	if err != nil {
 		return nil, err
 	}
-	return &Ref{iri: builder.String(), positions: pos}, nil
+	return newRef(builder.String(), pos), nil
+}
+
+// ResolveStrict is like Resolve but fails with an error wrapping
+// ErrPathAboveRoot when a ".." segment of the relative reference would go
+// above the root of the path. Resolve follows RFC 3986, Section 5.4.2, and
+// silently discards such segments, so that "../../../g" resolved against
+// "http://a/b" gives "http://a/g"; in security-sensitive contexts, such as
+// mapping IRIs to files, this usually denotes an attempt to escape the base.
+func (r *Ref) ResolveStrict(relativeIRI string) (*Ref, error) {
+	normalizedRelativeIRI := norm.NFC.String(relativeIRI)
+	builder := &strings.Builder{}
+	builder.Grow(len(r.iri) + len(normalizedRelativeIRI)) // Pre-allocate for efficiency.
+	b := &base{IRI: r.iri, Pos: r.positions.Positions}
+	pos, err := runResolveStrict(normalizedRelativeIRI, b, &stringOutputBuffer{builder: builder})
+	if err != nil {
+		return nil, newNormalizedParseError(err, relativeIRI, normalizedRelativeIRI)
+	}
+	return newRef(builder.String(), pos), nil
+}
+
+// ResolveWithMaxLength is like Resolve but fails with an error wrapping
+// ErrTooLong if either the relative reference or the resolved IRI is longer
+// than maxLen bytes. Both are checked because resolution can make an IRI
+// longer, by merging it with the base, as well as shorter, by removing dot
+// segments. A maxLen of zero or less disables the limit.
+func (r *Ref) ResolveWithMaxLength(relativeIRI string, maxLen int) (*Ref, error) {
+	normalizedRelativeIRI := norm.NFC.String(relativeIRI)
+	builder := &strings.Builder{}
+	b := &base{IRI: r.iri, Pos: r.positions.Positions}
+	pos, err := runWithLimits(normalizedRelativeIRI, b, false, &stringOutputBuffer{builder: builder}, maxLen)
+	if err != nil {
+		return nil, newNormalizedParseError(err, relativeIRI, normalizedRelativeIRI)
+	}
+	return newRef(builder.String(), pos), nil
+}
+
+// ResolveRef resolves an already parsed reference against the current Ref
+// (which acts as the base IRI). Unlike Resolve, the stored string of the
+// reference is used as-is, without NFC normalization, and the validation a
+// relative reference went through when it was parsed is not repeated. This
+// makes it cheaper to resolve many references against the same base. A
+// relative reference parsed with Options.Unchecked must therefore be known
+// to be valid, as required by that option.
+//
+// As per RFC 3986, Section 5.2.2, the scheme of an absolute reference wins, so
+// an absolute rel is not merged with the base. It goes through the same path
+// as with Resolve, which validates it again and, like Resolve, keeps its path
+// as-is: its dot-segments are only removed by Normalize.
+func (r *Ref) ResolveRef(rel *Ref) (*Ref, error) {
+	builder := &strings.Builder{}
+	builder.Grow(len(r.iri) + len(rel.iri)) // Pre-allocate for efficiency.
+	b := &base{IRI: r.iri, Pos: r.positions.Positions}
+	output := &stringOutputBuffer{builder: builder}
+	var pos DetailedPositions
+	var err error
+	if rel.IsAbsolute() {
+		pos, err = runDetailed(rel.iri, b, false, output)
+	} else {
+		pos, err = runResolveValidated(rel.iri, b, output)
+	}
+	if err != nil {
+		return nil, newParseError(err)
+	}
+	return newRef(builder.String(), pos), nil
+}
+
+// ResolveAgainst resolves r against base, which, unlike with Iri.Resolve, may
+// itself be a relative reference. This allows bases to be accumulated, e.g.,
+// resolving "c" against "a/b" gives "a/c", which can later be resolved
+// against an absolute IRI. The algorithm of RFC 3986, Section 5.2.2 is applied
+// with an empty base scheme, so the result is relative when base is. Unlike
+// the ".." segments that would go above the root of an absolute path, which
+// are dropped, those that would go above the start of a rootless path are
+// kept, e.g., resolving "../../c" against "a/b" gives "../c", so that
+// resolving through accumulated bases is the same as resolving each reference
+// in turn. A result whose path would start with "//"
+// without an authority is rejected, and a "./" is prepended to a relative
+// path whose first segment contains a colon, so that it is not mistaken for a
+// scheme.
+func (r *Ref) ResolveAgainst(base *Ref) (*Ref, error) {
+	resolved, err := base.ResolveRef(r)
+	if err != nil || resolved.IsAbsolute() || resolved.HasAuthority() {
+		return resolved, err
+	}
+	path := resolved.Path()
+	if firstSegment, _, _ := strings.Cut(path, "/"); strings.Contains(firstSegment, ":") {
+		return ParseRef("./" + resolved.iri)
+	}
+	return resolved, nil
 }
 
 // ResolveTo resolves a relative IRI reference and writes the result directly into
@@ -186,17 +479,43 @@ func (r *Ref) Resolve(relativeIRI string) (*Ref, error) {
 // of the components in the resulting IRI. This is useful for performance-critical code.
 // The relative IRI reference is normalized to NFC before resolution.
 func (r *Ref) ResolveTo(relativeIRI string, target *strings.Builder) (Positions, error) {
+	pos, err := r.resolveTo(relativeIRI, target)
+	return pos.Positions, err
+}
+
+// AppendResolved is like Resolve but appends the resolved IRI to dst and
+// returns the extended slice, for callers that need the result as bytes, e.g.,
+// to write it to a network buffer. The IRI is written directly into the spare
+// capacity of dst, without building an intermediate string. On error, dst is
+// returned unchanged.
+func (r *Ref) AppendResolved(dst []byte, relativeIRI string) ([]byte, error) {
+	normalizedRelativeIRI := norm.NFC.String(relativeIRI)
+	b := &base{IRI: r.iri, Pos: r.positions.Positions}
+	// The output starts empty, so that the positions computed by the parser
+	// do not depend on the content of dst.
+	output := &bytesOutputBuffer{buf: dst[len(dst):]}
+	if _, err := runDetailed(normalizedRelativeIRI, b, false, output); err != nil {
+		return dst, newNormalizedParseError(err, relativeIRI, normalizedRelativeIRI)
+	}
+	// If dst had enough capacity, the output was written in place, right
+	// after its content, and this only extends dst.
+	return append(dst, output.bytes()...), nil
+}
+
+// resolveTo implements ResolveTo, returning the detailed positions of the
+// resulting IRI so that Resolve can build a Ref from them.
+func (r *Ref) resolveTo(relativeIRI string, target *strings.Builder) (DetailedPositions, error) {
 	// Note: Normalizing the relative part here is a good practice for consistency
 	// of the resolved output, even if the base might not be normalized.
 	normalizedRelativeIRI := norm.NFC.String(relativeIRI)
 
-	b := &base{IRI: r.iri, Pos: r.positions}
+	b := &base{IRI: r.iri, Pos: r.positions.Positions}
 	output := &stringOutputBuffer{builder: target}
 
-	pos, err := run(normalizedRelativeIRI, b, false, output)
+	pos, err := runDetailed(normalizedRelativeIRI, b, false, output)
 
 	if err != nil {
-		return Positions{}, newParseError(err)
+		return DetailedPositions{}, newNormalizedParseError(err, relativeIRI, normalizedRelativeIRI)
 	}
 	return pos, nil
 }
@@ -208,6 +527,63 @@ func (r *Ref) String() string {
 	return r.iri
 }
 
+// Bytes returns the IRI reference as a byte slice. The slice is a fresh copy,
+// so the caller may modify it freely. To write the reference into an existing
+// buffer without the intermediate copy, use AppendTo.
+func (r *Ref) Bytes() []byte {
+	return []byte(r.iri)
+}
+
+// AppendTo appends the IRI reference to dst and returns the extended slice,
+// in the manner of the strconv Append functions. It is meant for encoders
+// building larger payloads and only allocates if dst must grow.
+func (r *Ref) AppendTo(dst []byte) []byte {
+	return append(dst, r.iri...)
+}
+
+// WriteTo implements the io.WriterTo interface, writing the IRI reference to w
+// in a single call. If w implements io.StringWriter, as bufio.Writer and
+// bytes.Buffer do, the reference is written without being copied first.
+func (r *Ref) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, r.iri)
+	return int64(n), err
+}
+
+// Equal reports whether two IRI references are equal using the simple
+// character-by-character comparison described in RFC 3987, Section 5.3.1.
+// No normalization is applied, so references that differ only in case or
+// percent-encoding are considered different. Two nil references are equal;
+// a nil reference is never equal to a non-nil one.
+func (r *Ref) Equal(other *Ref) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	return r.iri == other.iri
+}
+
+// Clone returns an independent copy of the IRI reference. The underlying
+// string is copied so that the clone does not share backing memory with the
+// original, which can matter when the original was built on top of a buffer
+// that is later reused. Cloning a nil Ref returns nil.
+func (r *Ref) Clone() *Ref {
+	if r == nil {
+		return nil
+	}
+	return newRef(strings.Clone(r.iri), r.positions)
+}
+
+// EqualNormalized reports whether two IRI references are equivalent after
+// syntax-based normalization (RFC 3987, Section 5.3.2). Both operands are
+// passed through Normalize, so differences in scheme or host case,
+// percent-encoding of unreserved characters, dot-segments, and default
+// ports are ignored. Nil references are handled as in Equal.
+func (r *Ref) EqualNormalized(other *Ref) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	return r.Normalize().Equal(other.Normalize())
+}
+
 // ToURI converts the IRI reference to a URI reference string, strictly following
 // RFC 3987, Section 3.1. It normalizes all components to NFC, percent-encodes
 // any non-ASCII characters using their UTF-8 representation, and applies IDNA
@@ -268,12 +644,52 @@ func (r *Ref) ToURI() string {
 	return builder.String()
 }
 
+// NFCNormalized reports whether the IRI reference is in Unicode
+// Normalization Form C, which is always the case for references returned by
+// ParseNormalizedRef or Normalize. It does not allocate.
+func (r *Ref) NFCNormalized() bool {
+	return norm.NFC.IsNormalString(r.iri)
+}
+
 // Normalize applies syntax-based normalization to the IRI reference according
 // to RFC 3986, Section 6.2.2. This includes case-normalization of the scheme
 // and host, percent-encoding normalization, and path-segment normalization.
 // It also ensures the resulting IRI is in Unicode Normalization Form C (NFC).
-// It returns a new, normalized Ref.
+// It returns a new, normalized Ref, or the receiver if it is already
+// normalized.
+//
+// The result is computed once and cached on the receiver, so later calls
+// return the same instance in constant time. Normalize is safe to call
+// concurrently on a shared Ref, even while it is copied (e.g., into an Iri);
+// copies share the cache of the Ref they were copied from.
 func (r *Ref) Normalize() *Ref {
+	if r.normalized == nil {
+		return r.normalize()
+	}
+	if cached := r.normalized.ref.Load(); cached != nil {
+		return cached
+	}
+	normalized := r.normalize()
+	// If another goroutine stored its result first, use it, so that every
+	// caller gets the same instance.
+	if !r.normalized.ref.CompareAndSwap(nil, normalized) {
+		return r.normalized.ref.Load()
+	}
+	return normalized
+}
+
+// Canonicalize is an alias for Normalize, for users of URI libraries where
+// this operation bears that name. The two methods are strictly equivalent:
+// both apply the syntax-based normalization of RFC 3986, Section 6.2.2 and the
+// scheme-based normalization of Section 6.2.3 (an empty path becomes "/" when
+// there is an authority, and the default port of the http, https, ws, wss, and
+// ftp schemes is removed), and return the same cached instance.
+func (r *Ref) Canonicalize() *Ref {
+	return r.Normalize()
+}
+
+// normalize computes the result of Normalize, without caching.
+func (r *Ref) normalize() *Ref {
 	if r.iri == "" {
 		return &Ref{}
 	}
@@ -291,15 +707,20 @@ func (r *Ref) Normalize() *Ref {
 	var userinfo, host, port string
 	if hasAuthority {
 		userinfo, host, port = splitAuthority(authority)
-		host, port = normalizeHostAndPort(host, port, scheme)
+		// The unreserved characters of the host are decoded first, so that
+		// they are lowercased like the literal ones (e.g., "EX%41MPLE" gives
+		// "example").
+		host, port = normalizeHostAndPort(normalizePercentEncoding(host), port, scheme)
 	}
 
-	// 2. Percent-Encoding Normalization
-	userinfo = normalizePercentEncoding(userinfo)
-	host = normalizePercentEncoding(host)
-	path = normalizePercentEncoding(path)
-	query = normalizePercentEncoding(query)
-	fragment = normalizePercentEncoding(fragment)
+	// 2. Percent-Encoding Normalization: decode unreserved characters and
+	// uppercase the hexadecimal digits of the remaining escapes. The host
+	// was already decoded above.
+	userinfo = uppercasePercentEncoding(normalizePercentEncoding(userinfo))
+	host = uppercasePercentEncoding(host)
+	path = uppercasePercentEncoding(normalizePercentEncoding(path))
+	query = uppercasePercentEncoding(normalizePercentEncoding(query))
+	fragment = uppercasePercentEncoding(normalizePercentEncoding(fragment))
 
 	// 3. Path Segment Normalization
 	path = removeDotSegments(path)
@@ -329,11 +750,44 @@ func (r *Ref) Normalize() *Ref {
 	return newRef
 }
 
+// NormalizePercentEncoding returns a Ref in which every percent-encoded octet
+// uses uppercase hexadecimal digits (e.g., "%2f" becomes "%2F"), as recommended
+// by RFC 3986, Section 6.2.2.1. Unlike Normalize, it does not decode any octet,
+// so reserved characters stay encoded and the meaning of the IRI is preserved.
+// A '%' that does not start a valid escape is left as-is. If the reference is
+// already in this form, the receiver itself is returned.
+func (r *Ref) NormalizePercentEncoding() *Ref {
+	normalized := uppercasePercentEncoding(r.iri)
+	if normalized == r.iri {
+		return r
+	}
+	// The length is unchanged, so the positions still apply.
+	return newRef(normalized, r.positions)
+}
+
 // IsAbsolute returns true if the IRI reference is absolute (i.e., it has a scheme).
 func (r *Ref) IsAbsolute() bool {
 	return r.positions.SchemeEnd != 0
 }
 
+// HasAuthority returns true if the IRI reference has an authority component,
+// even an empty one (e.g., "file:///a"). It is computed from the parsed
+// positions in constant time.
+func (r *Ref) HasAuthority() bool {
+	return r.positions.AuthorityEnd > r.positions.SchemeEnd
+}
+
+// IsHierarchical returns true if the IRI reference has an authority or a path
+// starting with "/" (e.g., "http://a/b" or "/a/b"), as opposed to an opaque
+// reference such as "mailto:user@example.com" or "urn:isbn:0451450523".
+// It is computed from the parsed positions in constant time.
+func (r *Ref) IsHierarchical() bool {
+	if r.HasAuthority() {
+		return true
+	}
+	return r.positions.PathEnd > r.positions.AuthorityEnd && r.iri[r.positions.AuthorityEnd] == '/'
+}
+
 // Scheme returns the scheme component of the IRI (e.g., "http") and a boolean
 // indicating whether it was present.
 func (r *Ref) Scheme() (string, bool) {
@@ -344,10 +798,28 @@ func (r *Ref) Scheme() (string, bool) {
 	return r.iri[:r.positions.SchemeEnd-1], true
 }
 
+// SchemeIs reports whether the IRI reference has a scheme equal to one of the
+// given schemes, e.g. SchemeIs("http", "https"). Schemes are case-insensitive
+// (RFC 3986, Section 3.1), and Scheme returns the scheme as written, which is
+// only lowercase after normalization, so the comparison ignores case:
+// "HTTP://a" matches "http". It returns false if the reference is relative.
+func (r *Ref) SchemeIs(schemes ...string) bool {
+	scheme, ok := r.Scheme()
+	if !ok {
+		return false
+	}
+	for _, s := range schemes {
+		if strings.EqualFold(scheme, s) {
+			return true
+		}
+	}
+	return false
+}
+
 // Authority returns the authority component of the IRI (e.g., "example.com:80")
 // and a boolean indicating whether it was present. The leading "//" is not included.
 func (r *Ref) Authority() (string, bool) {
-	if r.positions.AuthorityEnd <= r.positions.SchemeEnd {
+	if !r.HasAuthority() {
 		return "", false
 	}
 
@@ -355,12 +827,105 @@ func (r *Ref) Authority() (string, bool) {
 	return strings.TrimPrefix(authorityComponent, "//"), true
 }
 
+// Host returns the host subcomponent of the authority (e.g., "example.com"),
+// without any userinfo or port, and a boolean indicating whether an authority
+// was present. IP literals are returned with their enclosing brackets, exactly
+// as they appear in the IRI (e.g., "[::1]" or "[v1.fe80::a]"), so that the
+// result can be written back into an authority unchanged. The host may be
+// empty even when an authority is present (e.g., "http://" or "http://user@").
+func (r *Ref) Host() (string, bool) {
+	if !r.HasAuthority() {
+		return "", false
+	}
+	return r.iri[r.positions.UserInfoEnd:r.positions.HostEnd], true
+}
+
+// Port returns the port subcomponent of the authority (the digits after the
+// final ':' that is not part of an IP literal) and a boolean indicating whether
+// a non-empty port was present. An empty port, as in "http://example.com:",
+// is reported as absent.
+func (r *Ref) Port() (string, bool) {
+	if !r.HasAuthority() || r.positions.HostEnd >= r.positions.AuthorityEnd {
+		return "", false
+	}
+	// Skip the ':' delimiter.
+	port := r.iri[r.positions.HostEnd+1 : r.positions.AuthorityEnd]
+	return port, port != ""
+}
+
+// UserInfo returns the userinfo subcomponent of the authority (everything
+// before the last '@', e.g., "user:pass") and a boolean indicating whether an
+// '@' delimiter was present.
+func (r *Ref) UserInfo() (string, bool) {
+	if !r.HasAuthority() {
+		return "", false
+	}
+	// Skip the "//" prefix.
+	start := r.positions.SchemeEnd + authorityPrefixLength
+	if r.positions.UserInfoEnd <= start {
+		return "", false
+	}
+	// Drop the '@' delimiter.
+	return r.iri[start : r.positions.UserInfoEnd-1], true
+}
+
+// SplitAuthority returns the userinfo, host, and port subcomponents of the
+// authority at once, as returned by UserInfo, Host, and Port, and a boolean
+// indicating whether an authority was present. Absent and empty subcomponents
+// are both returned as empty strings; IP literals keep their brackets, so the
+// colons of an IPv6 address are never mistaken for a port delimiter.
+func (r *Ref) SplitAuthority() (string, string, string, bool) {
+	if !r.HasAuthority() {
+		return "", "", "", false
+	}
+	userinfo, _ := r.UserInfo()
+	host, _ := r.Host()
+	port, _ := r.Port()
+	return userinfo, host, port, true
+}
+
 // Path returns the path component of the IRI. A path is always present,
 // though it may be an empty string.
 func (r *Ref) Path() string {
 	return r.iri[r.positions.AuthorityEnd:r.positions.PathEnd]
 }
 
+// PathSegments returns the path split on '/' boundaries. Empty segments
+// produced by leading, trailing, or doubled slashes are preserved, so
+// "/a//b/" yields ["", "a", "", "b", ""]. Segments are returned as they appear
+// in the IRI, without percent-decoding, so an encoded slash ("%2F") never
+// introduces a new segment boundary. A reference with an empty path
+// (e.g., "http://a?b") yields an empty slice.
+func (r *Ref) PathSegments() []string {
+	path := r.Path()
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// ContainsDotSegments reports whether the path has a complete "." or ".."
+// segment, such as "/a/./b" or "/a/../b", which Relativize rejects. Segments
+// merely containing dots, such as ".g" or "b..", do not count, and neither do
+// percent-encoded dots. Normalize removes the dot-segments.
+func (r *Ref) ContainsDotSegments() bool {
+	return hasDotSegments(r.Path())
+}
+
+// PathSegmentsDecoded is like PathSegments but percent-decodes each segment
+// individually after splitting. Because the path is split first, a decoded
+// segment may contain a '/' that was encoded as "%2F" in the IRI.
+func (r *Ref) PathSegmentsDecoded() []string {
+	segments := r.PathSegments()
+	for i, segment := range segments {
+		// An error is not expected here as the path was validated during parsing.
+		if decoded, err := percentDecode(segment); err == nil {
+			segments[i] = decoded
+		}
+	}
+	return segments
+}
+
 // Query returns the query component of the IRI (the part after "?", without the "?")
 // and a boolean indicating whether it was present.
 func (r *Ref) Query() (string, bool) {
@@ -371,6 +936,36 @@ func (r *Ref) Query() (string, bool) {
 	return r.iri[r.positions.PathEnd+1 : r.positions.QueryEnd], true
 }
 
+// QueryParams parses the query component as application/x-www-form-urlencoded
+// data. The query is split on '&', each pair is split on its first '=', and
+// both key and value are percent-decoded, with '+' decoding to a space. A key
+// without '=' maps to an empty value and empty pairs are skipped. It returns
+// an empty url.Values if the IRI has no query, and an error if a pair contains
+// malformed percent-encoding.
+func (r *Ref) QueryParams() (url.Values, error) {
+	params := url.Values{}
+	query, ok := r.Query()
+	if !ok {
+		return params, nil
+	}
+	for pair := range strings.SplitSeq(query, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		decodedKey, err := percentDecode(strings.ReplaceAll(key, "+", " "))
+		if err != nil {
+			return nil, newParseError(err)
+		}
+		decodedValue, err := percentDecode(strings.ReplaceAll(value, "+", " "))
+		if err != nil {
+			return nil, newParseError(err)
+		}
+		params.Add(decodedKey, decodedValue)
+	}
+	return params, nil
+}
+
 // Fragment returns the fragment component of the IRI (the part after "#", without the "#")
 // and a boolean indicating whether it was present.
 func (r *Ref) Fragment() (string, bool) {
@@ -381,6 +976,44 @@ func (r *Ref) Fragment() (string, bool) {
 	return r.iri[r.positions.QueryEnd+1:], true
 }
 
+// Fragmentless returns the IRI reference without its fragment, e.g.
+// "http://a/b?c" for "http://a/b?c#d", which is convenient as a cache key.
+// Removing a component cannot make a reference invalid, so the string is only
+// sliced and not parsed again. If the reference has no fragment, r itself is
+// returned.
+func (r *Ref) Fragmentless() *Ref {
+	if r.positions.QueryEnd >= len(r.iri) {
+		return r
+	}
+	return newRef(r.iri[:r.positions.QueryEnd], r.positions)
+}
+
+// WithoutQueryAndFragment returns the IRI reference without its query and
+// fragment, e.g. "http://a/b" for "http://a/b?c#d". Like Fragmentless, it
+// only slices the string, and returns r itself if there is nothing to remove.
+func (r *Ref) WithoutQueryAndFragment() *Ref {
+	if r.positions.PathEnd >= len(r.iri) {
+		return r
+	}
+	positions := r.positions
+	positions.QueryEnd = positions.PathEnd
+	return newRef(r.iri[:positions.PathEnd], positions)
+}
+
+// Relativize computes a relative IRI reference that, when resolved against
+// the base reference `r`, results in `target`. Both references must be
+// absolute; if either lacks a scheme, an error wrapping ErrIriRelativize is
+// returned. Otherwise it behaves exactly like Iri.Relativize.
+func (r *Ref) Relativize(target *Ref) (*Ref, error) {
+	if !r.IsAbsolute() {
+		return nil, &relativizeError{reason: "the base reference is relative"}
+	}
+	if !target.IsAbsolute() {
+		return nil, &relativizeError{reason: "the target reference is relative"}
+	}
+	return (&Iri{Ref: *r}).Relativize(&Iri{Ref: *target})
+}
+
 // MarshalJSON implements the json.Marshaler interface, encoding the Ref as a JSON string.
 func (r *Ref) MarshalJSON() ([]byte, error) {
 	return json.Marshal(r.iri)
@@ -402,6 +1035,24 @@ func (r *Ref) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalText implements the encoding.TextMarshaler interface, returning the
+// IRI reference as UTF-8 bytes.
+func (r *Ref) MarshalText() ([]byte, error) {
+	return []byte(r.iri), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. Like
+// UnmarshalJSON, it validates the text with ParseRef and does not perform NFC
+// normalization. Invalid input results in a *ParseError.
+func (r *Ref) UnmarshalText(text []byte) error {
+	newRef, err := ParseRef(string(text))
+	if err != nil {
+		return err
+	}
+	*r = *newRef
+	return nil
+}
+
 // Iri represents a guaranteed absolute IRI. It embeds a Ref and provides convenience
 // methods for working with IRIs that must be absolute.
 type Iri struct {
@@ -412,7 +1063,16 @@ type Iri struct {
 // If the string is a relative reference, it returns an error. The string is not
 // NFC normalized; for that, use `ParseNormalizedIri`.
 func ParseIri(s string) (*Iri, error) {
-	ref, err := ParseRef(s)
+	ref, err := ParseWithOptions(s, Options{RequireScheme: true})
+	if err != nil {
+		return nil, err
+	}
+	return &Iri{Ref: *ref}, nil
+}
+
+// ParseIriBytes is like ParseIri but takes a byte slice. See ParseRefBytes.
+func ParseIriBytes(b []byte) (*Iri, error) {
+	ref, err := ParseRefBytes(b)
 	if err != nil {
 		return nil, err
 	}
@@ -443,6 +1103,45 @@ func (i *Iri) Scheme() string {
 	return s
 }
 
+// Equal reports whether two IRIs are equal using a character-by-character
+// comparison. See Ref.Equal for details.
+func (i *Iri) Equal(other *Iri) bool {
+	if i == nil || other == nil {
+		return i == other
+	}
+	return i.Ref.Equal(&other.Ref)
+}
+
+// Clone returns an independent copy of the IRI. See Ref.Clone for details.
+func (i *Iri) Clone() *Iri {
+	if i == nil {
+		return nil
+	}
+	return &Iri{Ref: *i.Ref.Clone()}
+}
+
+// Base returns the IRI truncated after the last "/" of its path, without
+// query and fragment, i.e., the "directory" against which relative references
+// are resolved, as with the HTML <base> element or xml:base. For example,
+// "http://a/b/c?q#f" gives "http://a/b/". A path without any "/" is dropped,
+// or replaced by "/" if the IRI has an authority, so "http://a" gives
+// "http://a/" and "urn:a:b" gives "urn:". The string is only sliced, and i
+// itself is returned if it is already in this form.
+func (i *Iri) Base() *Iri {
+	path := i.Path()
+	dir := path[:strings.LastIndex(path, "/")+1]
+	if dir == "" && i.HasAuthority() {
+		dir = "/"
+	}
+	if dir == path && i.positions.PathEnd == len(i.iri) {
+		return i
+	}
+	positions := i.positions
+	positions.PathEnd = positions.AuthorityEnd + len(dir)
+	positions.QueryEnd = positions.PathEnd
+	return &Iri{Ref: *newRef(i.iri[:positions.AuthorityEnd]+dir, positions)}
+}
+
 // Resolve resolves a relative IRI reference against the current Iri and returns
 // a new, absolute Iri.
 func (i *Iri) Resolve(relativeIRI string) (*Iri, error) {
@@ -454,6 +1153,43 @@ func (i *Iri) Resolve(relativeIRI string) (*Iri, error) {
 	return &Iri{Ref: *ref}, nil
 }
 
+// ResolveAll resolves a batch of relative IRI references against the current
+// Iri, as Resolve would for each of them. The components of the base are
+// extracted only once for the whole batch, which makes it cheaper than calling
+// Resolve in a loop, e.g. when processing an RDF document.
+//
+// The returned slices are parallel to rels: a reference that fails to resolve
+// does not abort the batch, and yields a nil result with its error at the same
+// index. The error at the index of a successful resolution is nil.
+func (i *Iri) ResolveAll(rels []string) ([]*Ref, []error) {
+	parserBase := &iriParserBase{
+		iri:          i.iri,
+		schemeEnd:    i.positions.SchemeEnd,
+		authorityEnd: i.positions.AuthorityEnd,
+		pathEnd:      i.positions.PathEnd,
+		queryEnd:     i.positions.QueryEnd,
+		hasBase:      true,
+	}
+	components := parserBase.extractComponents()
+	parserBase.components = &components
+
+	refs := make([]*Ref, len(rels))
+	errs := make([]error, len(rels))
+	var builder strings.Builder
+	for j, rel := range rels {
+		builder.Reset()
+		builder.Grow(len(i.iri) + len(rel))
+		normalizedRel := norm.NFC.String(rel)
+		pos, err := runWithParserBase(normalizedRel, parserBase, &stringOutputBuffer{builder: &builder})
+		if err != nil {
+			errs[j] = newNormalizedParseError(err, rel, normalizedRel)
+			continue
+		}
+		refs[j] = newRef(builder.String(), pos)
+	}
+	return refs, errs
+}
+
 // ResolveTo resolves a relative IRI and writes the resulting absolute IRI
 // to the provided strings.Builder, avoiding allocations.
 func (i *Iri) ResolveTo(relativeIRI string, target *strings.Builder) error {
@@ -481,22 +1217,56 @@ func (i *Iri) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalText implements the encoding.TextMarshaler interface.
+func (i *Iri) MarshalText() ([]byte, error) {
+	return i.Ref.MarshalText()
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, ensuring
+// the decoded IRI is absolute.
+func (i *Iri) UnmarshalText(text []byte) error {
+	newIri, err := ParseIri(string(text))
+	if err != nil {
+		return err
+	}
+	*i = *newIri
+	return nil
+}
+
 // Relativize computes a relative IRI reference that, when resolved against the
 // base IRI `i`, will result in the target IRI `abs`. This is the inverse of the
 // Resolve operation.
 //
 // The method will return the full target IRI or a scheme-relative IRI if the
 // schemes or authorities differ. It returns `ErrIriRelativize` if the target
-// IRI's path contains dot-segments ("." or "..").
+// IRI's path contains dot-segments ("." or ".."); see RelativizeNormalized.
 func (i *Iri) Relativize(abs *Iri) (*Ref, error) {
-	base := i
-	absPath := abs.Path()
+	if abs.ContainsDotSegments() {
+		return nil, ErrIriRelativize
+	}
+	return i.relativize(abs)
+}
 
-	for _, segment := range strings.Split(absPath, "/") {
-		if segment == "." || segment == ".." {
-			return nil, ErrIriRelativize
+// RelativizeNormalized is like Relativize but accepts a target IRI whose path
+// contains dot-segments: they are removed first, as NormalizePath does, so
+// that relativizing "http://a/b/./d" against "http://a/b/c" gives "d". The
+// other components of the target are left as they are. The result resolves
+// against i to the target without its dot-segments.
+func (i *Iri) RelativizeNormalized(abs *Iri) (*Ref, error) {
+	if abs.ContainsDotSegments() {
+		normalized, err := abs.WithPath(removeDotSegments(abs.Path()))
+		if err != nil {
+			return nil, err
 		}
+		abs = normalized
 	}
+	return i.relativize(abs)
+}
+
+// relativize implements Relativize for a target IRI without dot-segments.
+func (i *Iri) relativize(abs *Iri) (*Ref, error) {
+	base := i
+	absPath := abs.Path()
 
 	if base.Scheme() != abs.Scheme() {
 		return ParseRef(abs.String())
@@ -17,8 +17,12 @@ limitations under the License.
 package iri
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"strings"
+	"sync"
+	"unicode"
 )
 
 const (
@@ -35,6 +39,20 @@ type Positions struct {
 	QueryEnd     int
 }
 
+// DetailedPositions extends Positions with the boundaries of the authority
+// subcomponents, so that the userinfo, host, and port can be extracted
+// without rescanning the authority.
+//
+// UserInfoEnd is the index at which the host starts, just after the '@'
+// delimiter when a userinfo is present. HostEnd is the index at which the
+// host ends, on the ':' delimiter when a port is present. When the IRI has no
+// authority, both are equal to AuthorityEnd.
+type DetailedPositions struct {
+	Positions
+	UserInfoEnd int
+	HostEnd     int
+}
+
 // base represents a pre-parsed, absolute IRI that can be used as a base for
 // resolving relative references.
 type base struct {
@@ -42,12 +60,25 @@ type base struct {
 	Pos Positions
 }
 
-// run is the main entry point for the IRI parser. It parses, validates, and
-// resolves an IRI reference against an optional base IRI.
-func run(iri string, baseIRI *base, unchecked bool, output outputBuffer) (Positions, error) {
-	var b *iriParserBase
+// parserPool recycles iriParser instances, together with their base and
+// input, across calls to run. This avoids several small allocations per parse
+// on hot paths.
+//
+//nolint:gochecknoglobals // A package-level pool is required to share parsers across calls.
+var parserPool = sync.Pool{
+	New: func() any {
+		return &iriParser{
+			base:  &iriParserBase{},
+			input: newParserInput(""),
+		}
+	},
+}
+
+// acquireParser takes a parser from the pool and initializes it for a new run.
+func acquireParser(iri string, baseIRI *base, unchecked bool, output outputBuffer) *iriParser {
+	p, _ := parserPool.Get().(*iriParser)
 	if baseIRI != nil {
-		b = &iriParserBase{
+		*p.base = iriParserBase{
 			iri:          baseIRI.IRI,
 			schemeEnd:    baseIRI.Pos.SchemeEnd,
 			authorityEnd: baseIRI.Pos.AuthorityEnd,
@@ -55,23 +86,245 @@ func run(iri string, baseIRI *base, unchecked bool, output outputBuffer) (Positi
 			queryEnd:     baseIRI.Pos.QueryEnd,
 			hasBase:      true,
 		}
-	} else {
-		b = &iriParserBase{hasBase: false}
 	}
+	p.iri = iri
+	p.input.reset(iri)
+	p.output = output
+	p.unchecked = unchecked
+	return p
+}
+
+// releaseParser fully resets a parser, so that no state can leak into the
+// next run, and returns it to the pool.
+func releaseParser(p *iriParser) {
+	*p.base = iriParserBase{}
+	p.input.reset("")
+	p.iri = ""
+	p.output = nil
+	p.outputPositions = Positions{}
+	p.outputUserInfoEnd = 0
+	p.outputHostEnd = 0
+	p.inputSchemeEnd = 0
+	p.inputOffset = 0
+	p.unchecked = false
+	p.allowBidiMix = false
+	p.canonicalize = false
+	p.relativeValidated = false
+	p.strictDotSegments = false
+	p.pathAboveRoot = false
+	parserPool.Put(p)
+}
 
-	p := &iriParser{
-		iri:       iri,
-		base:      b,
-		input:     newParserInput(iri),
-		output:    output,
-		unchecked: unchecked,
+// run is the main entry point for the IRI parser. It parses, validates, and
+// resolves an IRI reference against an optional base IRI. When there is
+// nothing to resolve nor validate, the components are only located with
+// scanPositions.
+func run(iri string, baseIRI *base, unchecked bool, output outputBuffer) (Positions, error) {
+	if baseIRI == nil && unchecked {
+		pos, err := scanPositions(iri, output)
+		return pos.Positions, err
 	}
+	p := acquireParser(iri, baseIRI, unchecked, output)
+	defer releaseParser(p)
 
 	err := p.parseSchemeStart()
 	return p.outputPositions, err
 }
 
+// scanPositions is the fast path of run and runWithOptions for unchecked
+// parsing without a base. The output is then a copy of the input, so the
+// component boundaries are found with a single forward scan instead of the
+// state machine. For valid inputs, the positions are the same as those
+// computed by the state machine; invalid inputs, which unchecked parsing must
+// not be given, may be accepted where the state machine would fail.
+func scanPositions(iri string, output outputBuffer) (DetailedPositions, error) {
+	var pos DetailedPositions
+	if !strings.HasPrefix(iri, "//") {
+		if strings.HasPrefix(iri, ":") {
+			return DetailedPositions{}, &offsetError{err: errNoScheme, offset: 0}
+		}
+		pos.SchemeEnd = schemeLength(iri)
+	}
+
+	pos.AuthorityEnd = pos.SchemeEnd
+	pos.UserInfoEnd = pos.SchemeEnd
+	pos.HostEnd = pos.SchemeEnd
+	if strings.HasPrefix(iri[pos.SchemeEnd:], "//") {
+		start := pos.SchemeEnd + authorityPrefixLength
+		pos.AuthorityEnd = start + componentLength(iri[start:], "/?#")
+		pos.UserInfoEnd, pos.HostEnd = scanAuthority(iri[:pos.AuthorityEnd], start)
+	}
+	pos.PathEnd = pos.AuthorityEnd + componentLength(iri[pos.AuthorityEnd:], "?#")
+	pos.QueryEnd = pos.PathEnd
+	if pos.PathEnd < len(iri) && iri[pos.PathEnd] == '?' {
+		pos.QueryEnd = pos.PathEnd + componentLength(iri[pos.PathEnd:], "#")
+	}
+
+	output.writeString(iri)
+	return pos, nil
+}
+
+// scanAuthority returns the ends of the userinfo, including its '@'
+// delimiter, and of the host of the authority starting at start in iri, which
+// ends with the authority. The boundaries are those used by splitAuthority.
+func scanAuthority(iri string, start int) (int, int) {
+	userInfoEnd := start
+	if i := strings.LastIndexByte(iri[start:], '@'); i >= 0 {
+		userInfoEnd = start + i + 1
+	}
+	hostport := iri[userInfoEnd:]
+	hostLen := len(hostport)
+	if strings.HasPrefix(hostport, "[") {
+		if i := strings.LastIndexByte(hostport, ']'); i >= 0 {
+			hostLen = i + 1
+		}
+	} else if i := strings.LastIndexByte(hostport, ':'); i >= 0 {
+		hostLen = i
+	}
+	return userInfoEnd, userInfoEnd + hostLen
+}
+
+// schemeLength returns the length of the scheme of iri, including the ':'
+// delimiter, or 0 if iri does not start with a scheme.
+func schemeLength(iri string) int {
+	if iri == "" || !isASCIILetter(rune(iri[0])) {
+		return 0
+	}
+	for i := 1; i < len(iri); i++ {
+		c := rune(iri[i])
+		switch {
+		case c == ':':
+			return i + 1
+		case !isASCIILetter(c) && !isASCIIDigit(c) && c != '+' && c != '-' && c != '.':
+			return 0
+		}
+	}
+	return 0
+}
+
+// componentLength returns the length of the component at the start of s,
+// which ends at the first of the delimiters or at the end of s.
+func componentLength(s, delimiters string) int {
+	if i := strings.IndexAny(s, delimiters); i >= 0 {
+		return i
+	}
+	return len(s)
+}
+
+// runDetailed is like run but also returns the boundaries of the userinfo and
+// host within the authority.
+func runDetailed(iri string, baseIRI *base, unchecked bool, output outputBuffer) (DetailedPositions, error) {
+	p := acquireParser(iri, baseIRI, unchecked, output)
+	defer releaseParser(p)
+
+	if err := p.parseSchemeStart(); err != nil {
+		return DetailedPositions{}, err
+	}
+	return p.detailedPositions(), nil
+}
+
+// runWithLimits is like runDetailed but fails with an error wrapping
+// ErrTooLong if the input or the output is longer than maxLen bytes. The input
+// is checked upfront, so that an oversized IRI is rejected without being
+// parsed, and the output afterwards, since resolution can make it longer than
+// the input. A maxLen of zero or less disables both checks.
+func runWithLimits(
+	iri string, baseIRI *base, unchecked bool, output outputBuffer, maxLen int,
+) (DetailedPositions, error) {
+	if maxLen > 0 && len(iri) > maxLen {
+		return DetailedPositions{}, fmt.Errorf(
+			"%w: the input is %d bytes long, the limit is %d", ErrTooLong, len(iri), maxLen,
+		)
+	}
+	pos, err := runDetailed(iri, baseIRI, unchecked, output)
+	if err != nil {
+		return DetailedPositions{}, err
+	}
+	if maxLen > 0 && output.len() > maxLen {
+		return DetailedPositions{}, fmt.Errorf(
+			"%w: the output is %d bytes long, the limit is %d", ErrTooLong, output.len(), maxLen,
+		)
+	}
+	return pos, nil
+}
+
+// runWithOptions is like runDetailed but applies the given parsing options.
+// Unchecked parsing only locates the components, with scanPositions, unless
+// the output has to be canonicalized.
+func runWithOptions(iri string, opts Options, output outputBuffer) (DetailedPositions, error) {
+	if opts.Unchecked && !opts.Canonicalize {
+		return scanPositions(iri, output)
+	}
+	p := acquireParser(iri, nil, opts.Unchecked, output)
+	defer releaseParser(p)
+	p.allowBidiMix = opts.AllowBidiMix
+	p.canonicalize = opts.Canonicalize
+
+	if err := p.parseSchemeStart(); err != nil {
+		return DetailedPositions{}, err
+	}
+	return p.detailedPositions(), nil
+}
+
+// runResolveStrict is like runDetailed but fails with an error wrapping
+// ErrPathAboveRoot if a ".." segment of the relative reference tries to go
+// above the root of the path during resolution.
+func runResolveStrict(relativeRef string, baseIRI *base, output outputBuffer) (DetailedPositions, error) {
+	p := acquireParser(relativeRef, baseIRI, false, output)
+	defer releaseParser(p)
+	p.strictDotSegments = true
+
+	if err := p.parseSchemeStart(); err != nil {
+		return DetailedPositions{}, err
+	}
+	return p.detailedPositions(), nil
+}
+
+// runWithParserBase is like runDetailed but takes a prepared parser base, so
+// that a base can be shared, along with its extracted components, by several
+// resolutions.
+func runWithParserBase(iri string, parserBase *iriParserBase, output outputBuffer) (DetailedPositions, error) {
+	p := acquireParser(iri, nil, false, output)
+	defer releaseParser(p)
+	*p.base = *parserBase
+
+	if err := p.parseSchemeStart(); err != nil {
+		return DetailedPositions{}, err
+	}
+	return p.detailedPositions(), nil
+}
+
+// runResolveValidated resolves a relative reference that has already been
+// validated by a previous parse against a base IRI. It skips the validation
+// sub-parse of the reference and returns the detailed positions of the result.
+func runResolveValidated(relativeRef string, baseIRI *base, output outputBuffer) (DetailedPositions, error) {
+	p := acquireParser(relativeRef, baseIRI, false, output)
+	defer releaseParser(p)
+	p.relativeValidated = true
+
+	if err := p.parseSchemeStart(); err != nil {
+		return DetailedPositions{}, err
+	}
+	return p.detailedPositions(), nil
+}
+
+// detailedPositions assembles the DetailedPositions of a completed parse.
+func (p *iriParser) detailedPositions() DetailedPositions {
+	pos := DetailedPositions{Positions: p.outputPositions}
+	if pos.AuthorityEnd > pos.SchemeEnd {
+		pos.UserInfoEnd = p.outputUserInfoEnd
+		pos.HostEnd = p.outputHostEnd
+	} else {
+		// The authority markers are only recorded when an authority is parsed.
+		pos.UserInfoEnd = pos.AuthorityEnd
+		pos.HostEnd = pos.AuthorityEnd
+	}
+	return pos
+}
+
 // iriParserBase holds the component data of a base IRI used for resolution.
+// The components are only filled when they are extracted ahead of a batch of
+// resolutions; otherwise they are extracted on demand.
 type iriParserBase struct {
 	iri          string
 	schemeEnd    int
@@ -79,17 +332,38 @@ type iriParserBase struct {
 	pathEnd      int
 	queryEnd     int
 	hasBase      bool
+	components   *baseComponents
 }
 
 // iriParser holds the state for a single parsing operation.
 type iriParser struct {
-	iri             string
-	base            *iriParserBase
-	input           *parserInput
-	output          outputBuffer
-	outputPositions Positions
-	inputSchemeEnd  int
-	unchecked       bool
+	iri               string
+	base              *iriParserBase
+	input             *parserInput
+	output            outputBuffer
+	outputPositions   Positions
+	outputUserInfoEnd int
+	outputHostEnd     int
+	inputSchemeEnd    int
+	inputOffset       int
+	unchecked         bool
+	allowBidiMix      bool
+	canonicalize      bool
+	relativeValidated bool
+	strictDotSegments bool
+	pathAboveRoot     bool
+}
+
+// errorAt records that err occurred at the given position of the parser
+// input. The input of a sub-parser starts at inputOffset in the input of the
+// main parser, so that the recorded offset is always relative to the latter.
+// An error that already has an offset is returned unchanged.
+func (p *iriParser) errorAt(pos int, err error) error {
+	var oe *offsetError
+	if errors.As(err, &oe) {
+		return err
+	}
+	return &offsetError{err: err, offset: p.inputOffset + pos}
 }
 
 // parseSchemeStart is the initial state of the parser.
@@ -112,7 +386,7 @@ func (p *iriParser) parseSchemeStart() error {
 		return p.parseRelative()
 	}
 	if r == ':' {
-		return errNoScheme
+		return p.errorAt(p.input.position(), errNoScheme)
 	}
 	if isASCIILetter(r) {
 		return p.parseScheme()
@@ -136,6 +410,9 @@ func (p *iriParser) parseScheme() error {
 
 		switch {
 		case isASCIILetter(r) || isASCIIDigit(r) || r == '+' || r == '-' || r == '.':
+			if p.canonicalize {
+				r = unicode.ToLower(r)
+			}
 			p.output.writeRune(r)
 		case r == ':':
 			p.output.writeRune(':')
@@ -179,7 +456,7 @@ func (p *iriParser) parsePathOrAuthority() error {
 func (p *iriParser) parsePathStart(r rune, ok bool) error {
 	if !ok {
 		// End of input after authority (e.g., "http://host.com")
-		p.outputPositions.PathEnd = p.output.len()
+		p.endPath()
 		p.outputPositions.QueryEnd = p.output.len()
 		return nil
 	}
@@ -188,12 +465,12 @@ func (p *iriParser) parsePathStart(r rune, ok bool) error {
 	switch r {
 	case '?':
 		p.input.next() // Consume '?'
-		p.outputPositions.PathEnd = p.output.len()
+		p.endPath()
 		p.output.writeRune('?')
 		return p.parseQuery()
 	case '#':
 		p.input.next() // Consume '#'
-		p.outputPositions.PathEnd = p.output.len()
+		p.endPath()
 		p.outputPositions.QueryEnd = p.output.len()
 		p.output.writeRune('#')
 		return p.parseFragment()
@@ -223,7 +500,9 @@ func (p *iriParser) parsePathNoScheme() error {
 		if c == ':' {
 			// RFC 3986, Section 4.2: A path segment that contains a colon
 			// cannot be used as the first segment of a relative-path reference.
-			return &kindError{message: "Invalid IRI character in first path segment", char: c}
+			return p.errorAt(
+				p.input.position(), &kindError{message: "Invalid IRI character in first path segment", char: c},
+			)
 		}
 		p.input.next()
 		if err := p.readURLCodepointOrEchar(c, func(r rune) bool {
@@ -236,30 +515,35 @@ func (p *iriParser) parsePathNoScheme() error {
 }
 
 // validateBidiPart checks the bidi validity of the current component part if validation is enabled.
-func (p *iriParser) validateBidiPart(startIndex int) error {
-	if p.unchecked {
+// As with ParseRef, it is skipped when canonicalizing, so that the same references are accepted.
+// The part starts at startIndex in the output and at inputStart in the input.
+func (p *iriParser) validateBidiPart(startIndex, inputStart int) error {
+	if p.unchecked || p.allowBidiMix || p.canonicalize {
 		return nil
 	}
 	if _, ok := p.output.(*voidOutputBuffer); ok {
 		return nil
 	}
 	part := p.output.string()[startIndex:]
-	return validateBidiComponent(part)
+	if err := validateBidiComponent(part); err != nil {
+		return p.errorAt(inputStart, err)
+	}
+	return nil
 }
 
 // handlePathTerminator checks for and processes path terminators ('?' or '#').
 // It returns true if a terminator was found and handled, along with any error.
-func (p *iriParser) handlePathTerminator(c rune, segmentStartIndex int) (bool, error) {
+func (p *iriParser) handlePathTerminator(c rune, segmentStartIndex, segmentInputStart int) (bool, error) {
 	if c != '?' && c != '#' {
 		return false, nil
 	}
 
-	if err := p.validateBidiPart(segmentStartIndex); err != nil {
+	if err := p.validateBidiPart(segmentStartIndex, segmentInputStart); err != nil {
 		return true, err
 	}
 
 	p.input.next()
-	p.outputPositions.PathEnd = p.output.len()
+	p.endPath()
 
 	if c == '?' {
 		p.output.writeRune('?')
@@ -272,6 +556,25 @@ func (p *iriParser) handlePathTerminator(c rune, segmentStartIndex int) (bool, e
 	return true, p.parseFragment()
 }
 
+// endPath records the end of the path in the output. When canonicalizing, the
+// dot-segments of the path are removed first, and an empty path becomes "/"
+// if there is an authority, as Normalize does.
+func (p *iriParser) endPath() {
+	if p.canonicalize {
+		start := p.outputPositions.AuthorityEnd
+		path := p.output.string()[start:]
+		canonical := removeDotSegments(path)
+		if canonical == "" && p.outputPositions.AuthorityEnd > p.outputPositions.SchemeEnd {
+			canonical = "/"
+		}
+		if canonical != path {
+			p.output.truncate(start)
+			p.output.writeString(canonical)
+		}
+	}
+	p.outputPositions.PathEnd = p.output.len()
+}
+
 // isPathChar is a predicate for characters allowed in a path.
 func isPathChar(c rune) bool {
 	return isIUnreservedOrSubDelims(c) || c == ':' || c == '@' || c == '/'
@@ -282,6 +585,7 @@ func (p *iriParser) parsePath() error {
 	hasAuthority := p.outputPositions.AuthorityEnd > p.outputPositions.SchemeEnd
 	var prev rune
 	segmentStartIndex := p.output.len()
+	segmentInputStart := p.input.position()
 
 	for {
 		c, ok := p.input.peek()
@@ -289,7 +593,7 @@ func (p *iriParser) parsePath() error {
 			break
 		}
 
-		isTerminator, err := p.handlePathTerminator(c, segmentStartIndex)
+		isTerminator, err := p.handlePathTerminator(c, segmentStartIndex, segmentInputStart)
 		if isTerminator {
 			return err
 		}
@@ -297,12 +601,12 @@ func (p *iriParser) parsePath() error {
 		// RFC 3986, Section 3.3: if a URI does not contain an authority component,
 		// then the path cannot begin with two slash characters ("//").
 		if !hasAuthority && c == '/' && prev == '/' {
-			return errPathStartingWithSlashes
+			return p.errorAt(p.input.position(), errPathStartingWithSlashes)
 		}
 
 		p.input.next()
 		if c == '/' {
-			if err = p.validateBidiPart(segmentStartIndex); err != nil {
+			if err = p.validateBidiPart(segmentStartIndex, segmentInputStart); err != nil {
 				return err
 			}
 		}
@@ -312,15 +616,16 @@ func (p *iriParser) parsePath() error {
 		}
 		if c == '/' {
 			segmentStartIndex = p.output.len()
+			segmentInputStart = p.input.position()
 		}
 		prev = c
 	}
 
-	if err := p.validateBidiPart(segmentStartIndex); err != nil {
+	if err := p.validateBidiPart(segmentStartIndex, segmentInputStart); err != nil {
 		return err
 	}
 
-	p.outputPositions.PathEnd = p.output.len()
+	p.endPath()
 	p.outputPositions.QueryEnd = p.output.len()
 	return nil
 }
@@ -334,8 +639,8 @@ func isQueryChar(c rune) bool {
 }
 
 // handleQueryEnd handles the end of a query, either by EOF or a '#' terminator.
-func (p *iriParser) handleQueryEnd(isFragment bool, queryStart int) error {
-	if err := p.validateBidiPart(queryStart); err != nil {
+func (p *iriParser) handleQueryEnd(isFragment bool, queryStart, queryInputStart int) error {
+	if err := p.validateBidiPart(queryStart, queryInputStart); err != nil {
 		return err
 	}
 	p.outputPositions.QueryEnd = p.output.len()
@@ -350,13 +655,14 @@ func (p *iriParser) handleQueryEnd(isFragment bool, queryStart int) error {
 // parseQuery consumes the query component.
 func (p *iriParser) parseQuery() error {
 	queryStart := p.output.len()
+	queryInputStart := p.input.position()
 	for {
 		r, ok := p.input.peek()
 		if !ok {
-			return p.handleQueryEnd(false, queryStart)
+			return p.handleQueryEnd(false, queryStart, queryInputStart)
 		}
 		if r == '#' {
-			return p.handleQueryEnd(true, queryStart)
+			return p.handleQueryEnd(true, queryStart, queryInputStart)
 		}
 		p.input.next()
 		if err := p.readURLCodepointOrEchar(r, isQueryChar); err != nil {
@@ -368,11 +674,12 @@ func (p *iriParser) parseQuery() error {
 // parseFragment consumes the fragment component.
 func (p *iriParser) parseFragment() error {
 	fragmentStart := p.output.len()
+	fragmentInputStart := p.input.position()
 	for {
 		r, ok := p.input.next()
 		if !ok {
 			if !p.unchecked {
-				if err := p.validateBidiPart(fragmentStart); err != nil {
+				if err := p.validateBidiPart(fragmentStart, fragmentInputStart); err != nil {
 					return err
 				}
 			}
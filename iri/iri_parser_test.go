@@ -177,7 +177,7 @@ func TestValidateBidiPart(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			p := setupTestParser(tc.component, tc.unchecked)
 			p.output.writeString(tc.component)
-			err := p.validateBidiPart(0)
+			err := p.validateBidiPart(0, 0)
 
 			if (err != nil) != tc.wantErr {
 				t.Errorf("validateBidiPart() error = %v, wantErr %v", err, tc.wantErr)
@@ -269,7 +269,7 @@ func TestHandleQueryEnd(t *testing.T) {
 			p.output.writeString(tc.queryPart) // Simulate that a query has been parsed
 			queryStart := 0
 
-			err := p.handleQueryEnd(tc.isFragment, queryStart)
+			err := p.handleQueryEnd(tc.isFragment, queryStart, 0)
 
 			if (err != nil) != tc.wantErr {
 				t.Fatalf("handleQueryEnd() error = %v, wantErr %v", err, tc.wantErr)
@@ -385,7 +385,7 @@ func TestHandlePathTerminator(t *testing.T) {
 			p.output.writeString(tc.pathPart)
 			peekedChar, _ := p.input.peek()
 
-			handled, err := p.handlePathTerminator(peekedChar, 0)
+			handled, err := p.handlePathTerminator(peekedChar, 0, 0)
 
 			// Simple, flat checks
 			if (err != nil) != tc.wantErr {
@@ -491,6 +491,7 @@ func TestParsePathNoScheme(t *testing.T) {
 			},
 		},
 		{name: "Valid Colon in Second Segment", input: "a/b:c", expected: "a/b:c", expectedErr: nil},
+		{name: "Valid Encoded Colon in First Segment", input: "a%3Ab/c", expected: "a%3Ab/c", expectedErr: nil},
 		{name: "Ends with Query", input: "a/b?q", expected: "a/b?q", expectedErr: nil},
 	}
 
@@ -799,3 +800,251 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+// TestScanPositions is a differential test checking that the fast path of run
+// for unchecked parsing without a base finds the same positions, and writes
+// the same output, as the state machine.
+func TestScanPositions(t *testing.T) {
+	inputs := []string{
+		"https://example.com/p?q#f",
+		"ftp://host/path",
+		"//host/path",
+		"//user@host:8080?q",
+		"http://[::1]:80/a/b?c?d#e?f#g",
+		"http://example.com",
+		"http://example.com?q",
+		"http://example.com#f",
+		"http:",
+		"http:/a/b",
+		"http:#f",
+		"file:///etc/hosts",
+		"mailto:user@example.com",
+		"tag:example.com,2005:a/b",
+		"a+b-c.d:e",
+		"a[b",
+		"/a/b:c",
+		"./a:b",
+		"?a:b",
+		"#a:b",
+		"a/b?c:d",
+		"http://例子.com/résumé?q=é#é",
+		"//@host/a",
+		"//user@host:",
+		"http://u:p@[::1]:8080/a",
+		"http://[::1]",
+		"http://a@b@c:1",
+		"http://",
+	}
+	for _, tc := range validRefCases() {
+		inputs = append(inputs, tc.input)
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			fastOutput := &stringOutputBuffer{builder: &strings.Builder{}}
+			fastPos, fastErr := scanPositions(input, fastOutput)
+
+			output := &stringOutputBuffer{builder: &strings.Builder{}}
+			p := acquireParser(input, nil, true, output)
+			err := p.parseSchemeStart()
+			pos := p.detailedPositions()
+			releaseParser(p)
+
+			if err != nil || fastErr != nil {
+				t.Fatalf("Expected no error, got %v (state machine) and %v (fast path)", err, fastErr)
+			}
+			if fastPos != pos {
+				t.Errorf("Fast path positions = %+v, state machine positions = %+v", fastPos, pos)
+			}
+			if fastOutput.string() != output.string() {
+				t.Errorf("Fast path output = %q, state machine output = %q", fastOutput.string(), output.string())
+			}
+			if runPos, _ := run(input, nil, true, &voidOutputBuffer{}); runPos != pos.Positions {
+				t.Errorf("run positions = %+v, state machine positions = %+v", runPos, pos.Positions)
+			}
+		})
+	}
+
+	t.Run("No scheme", func(t *testing.T) {
+		_, err := run(":foo", nil, true, &voidOutputBuffer{})
+		assertError(t, err, errNoScheme)
+	})
+}
+
+// TestRunDetailed tests that the detailed entry point records the userinfo
+// and host boundaries, both when parsing and when resolving against a base.
+func TestRunDetailed(t *testing.T) {
+	baseIRI := &base{
+		IRI: "http://u@a:8/b/c",
+		Pos: Positions{SchemeEnd: 5, AuthorityEnd: 12, PathEnd: 16, QueryEnd: 16},
+	}
+
+	testCases := []struct {
+		name        string
+		input       string
+		base        *base
+		expectedPos DetailedPositions
+	}{
+		{
+			name:  "Full Authority",
+			input: "http://user:pw@example.com:8080/p",
+			expectedPos: DetailedPositions{
+				Positions:   Positions{SchemeEnd: 5, AuthorityEnd: 31, PathEnd: 33, QueryEnd: 33},
+				UserInfoEnd: 15,
+				HostEnd:     26,
+			},
+		},
+		{
+			name:  "Host Only",
+			input: "http://example.com/p",
+			expectedPos: DetailedPositions{
+				Positions:   Positions{SchemeEnd: 5, AuthorityEnd: 18, PathEnd: 20, QueryEnd: 20},
+				UserInfoEnd: 7,
+				HostEnd:     18,
+			},
+		},
+		{
+			name:  "IP Literal With Port",
+			input: "//[::1]:80",
+			expectedPos: DetailedPositions{
+				Positions:   Positions{SchemeEnd: 0, AuthorityEnd: 10, PathEnd: 10, QueryEnd: 10},
+				UserInfoEnd: 2,
+				HostEnd:     7,
+			},
+		},
+		{
+			name:  "Empty Userinfo And Port",
+			input: "http://@a:",
+			expectedPos: DetailedPositions{
+				Positions:   Positions{SchemeEnd: 5, AuthorityEnd: 10, PathEnd: 10, QueryEnd: 10},
+				UserInfoEnd: 8,
+				HostEnd:     9,
+			},
+		},
+		{
+			name:  "No Authority",
+			input: "mailto:a@b",
+			expectedPos: DetailedPositions{
+				Positions:   Positions{SchemeEnd: 7, AuthorityEnd: 7, PathEnd: 10, QueryEnd: 10},
+				UserInfoEnd: 7,
+				HostEnd:     7,
+			},
+		},
+		{
+			name:  "Resolved Against Base Authority",
+			input: "d?q",
+			base:  baseIRI,
+			expectedPos: DetailedPositions{
+				Positions:   Positions{SchemeEnd: 5, AuthorityEnd: 12, PathEnd: 16, QueryEnd: 18},
+				UserInfoEnd: 9,
+				HostEnd:     10,
+			},
+		},
+		{
+			name:  "Resolved With Reference Authority",
+			input: "//x:9/y",
+			base:  baseIRI,
+			expectedPos: DetailedPositions{
+				Positions:   Positions{SchemeEnd: 5, AuthorityEnd: 10, PathEnd: 12, QueryEnd: 12},
+				UserInfoEnd: 7,
+				HostEnd:     8,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var output outputBuffer = &voidOutputBuffer{}
+			if tc.base != nil {
+				output = &stringOutputBuffer{builder: &strings.Builder{}}
+			}
+			pos, err := runDetailed(tc.input, tc.base, false, output)
+			if err != nil {
+				t.Fatalf("runDetailed() returned an unexpected error: %v", err)
+			}
+			if pos != tc.expectedPos {
+				t.Errorf("runDetailed() positions = %+v, want %+v", pos, tc.expectedPos)
+			}
+		})
+	}
+}
+
+// TestRunWithLimits tests that the maximum length is enforced on both the
+// input and the resolved output.
+func TestRunWithLimits(t *testing.T) {
+	baseIRI := &base{
+		IRI: "http://a/b/c/d",
+		Pos: Positions{SchemeEnd: 5, AuthorityEnd: 8, PathEnd: 14, QueryEnd: 14},
+	}
+
+	testCases := []struct {
+		name    string
+		input   string
+		base    *base
+		maxLen  int
+		tooLong bool
+	}{
+		{"Input within limit", "http://a/b", nil, 10, false},
+		{"Input too long", "http://a/bc", nil, 10, true},
+		{"No limit", "http://a/bc", nil, 0, false},
+		{"Negative limit", "http://a/bc", nil, -1, false},
+		{"Merge grows past limit", "e", baseIRI, 10, true},
+		{"Merge within limit", "e", baseIRI, 14, false},
+		{"Dot segments shrink within limit", "../../../../g", baseIRI, 13, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &stringOutputBuffer{builder: &strings.Builder{}}
+			_, err := runWithLimits(tc.input, tc.base, false, output, tc.maxLen)
+			if errors.Is(err, ErrTooLong) != tc.tooLong {
+				t.Errorf("runWithLimits() error = %v, want ErrTooLong: %v", err, tc.tooLong)
+			}
+			if !tc.tooLong && err != nil {
+				t.Errorf("runWithLimits() returned an unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestReleaseParser verifies that a parser returned to the pool carries no
+// state from its previous run.
+func TestReleaseParser(t *testing.T) {
+	baseIRI := &base{
+		IRI: "http://a/b/c",
+		Pos: Positions{SchemeEnd: 5, AuthorityEnd: 8, PathEnd: 12, QueryEnd: 12},
+	}
+	p := acquireParser("g?y#s", baseIRI, true, &voidOutputBuffer{})
+	if err := p.parseSchemeStart(); err != nil {
+		t.Fatalf("parseSchemeStart() returned an unexpected error: %v", err)
+	}
+	releaseParser(p)
+
+	if *p.base != (iriParserBase{}) {
+		t.Errorf("base was not reset: %+v", *p.base)
+	}
+	if p.iri != "" || p.input.originalString != "" || p.input.reader.Len() != 0 {
+		t.Errorf("input was not reset: iri=%q, input=%q", p.iri, p.input.originalString)
+	}
+	if p.output != nil || p.outputPositions != (Positions{}) || p.outputUserInfoEnd != 0 || p.outputHostEnd != 0 ||
+		p.inputSchemeEnd != 0 || p.unchecked || p.relativeValidated || p.strictDotSegments || p.pathAboveRoot {
+		t.Errorf("parser state was not reset: %+v", p)
+	}
+
+	// A parser acquired without a base must not see the previous base.
+	pos, err := run("g", nil, false, &voidOutputBuffer{})
+	if err != nil {
+		t.Fatalf("run() returned an unexpected error: %v", err)
+	}
+	if expected := (Positions{PathEnd: 1, QueryEnd: 1}); pos != expected {
+		t.Errorf("run() positions = %+v, want %+v", pos, expected)
+	}
+}
+
+// BenchmarkRun measures the allocations of a validation-only parse.
+func BenchmarkRun(b *testing.B) {
+	b.ReportAllocs()
+	for range b.N {
+		_, _ = run("http://user@example.com:8080/a/b/c?q=1#frag", nil, false, &voidOutputBuffer{})
+	}
+}
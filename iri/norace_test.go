@@ -0,0 +1,24 @@
+//go:build !race
+
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//nolint:testpackage // This is a white-box test file for an internal package. It needs to be in the same package to test unexported functions.
+package iri
+
+// raceEnabled reports whether the tests are run with the race detector, which
+// makes sync.Pool drop items at random, so that allocation counts vary.
+const raceEnabled = false
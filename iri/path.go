@@ -83,13 +83,37 @@ func extractFirstSegment(in string) (string, string) {
 	return in[:slashIndex], in[slashIndex:]
 }
 
+// hasDotSegments reports whether a path has a complete "." or ".." segment,
+// i.e., one that removeDotSegments would remove.
+func hasDotSegments(path string) bool {
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment == "." || segment == ".." {
+			return true
+		}
+	}
+	return false
+}
+
 // removeDotSegments implements the "Remove Dot Segments" algorithm from
 // RFC 3986, Section 5.2.4. It normalizes a path by resolving "." and ".." segments.
 func removeDotSegments(input string) string {
+	output, _ := removeDotSegmentsChecked(input)
+	return output
+}
+
+// removeDotSegmentsChecked is like removeDotSegments but also reports whether
+// a ".." segment tried to go above the root of an absolute path, e.g. in
+// "/a/../../b". The algorithm silently discards such segments, so this is the
+// only way to detect them.
+func removeDotSegmentsChecked(input string) (string, bool) {
 	var output []string
+	var escaped bool
 	in := input
 
 	for len(in) > 0 {
+		if len(output) == 0 && (strings.HasPrefix(in, "/../") || in == "/..") {
+			escaped = true
+		}
 		var ruleApplied bool
 		in, output, ruleApplied = applyDotSegmentRules(in, output)
 		if ruleApplied {
@@ -104,16 +128,111 @@ func removeDotSegments(input string) string {
 		output = append(output, segment)
 	}
 
-	return strings.Join(output, "")
+	return strings.Join(output, ""), escaped
+}
+
+// NormalizePath removes the dot-segments of a path with the algorithm of
+// RFC 3986, Section 5.2.4, as done when resolving and normalizing IRIs. It
+// applies to any slash-separated path, absolute or relative, such as a
+// file-like key: "/a/b/../c/./d" becomes "/a/c/d", and "a/../b" becomes "b".
+// A trailing "." or ".." segment leaves a trailing slash ("/a/b/.." becomes
+// "/a/"), ".." segments above the root of an absolute path are dropped, and
+// empty segments are kept, so "//" is left unchanged.
+func NormalizePath(p string) string {
+	return removeDotSegments(p)
 }
 
 // resolvePath resolves a relative path against a base path according to
 // RFC 3986, Section 5.2.2. It merges the base path with the relative
 // reference path.
 func resolvePath(basePath, relPath string) string {
+	path, _ := resolvePathChecked(basePath, relPath)
+	return path
+}
+
+// resolvePathChecked is like resolvePath but also reports whether the merged
+// path tried to go above its root, as removeDotSegmentsChecked does.
+func resolvePathChecked(basePath, relPath string) (string, bool) {
 	lastSlash := strings.LastIndex(basePath, "/")
 	if lastSlash == -1 {
-		return removeDotSegments(relPath)
+		return removeDotSegmentsChecked(relPath)
+	}
+	return removeDotSegmentsChecked(basePath[:lastSlash+1] + relPath)
+}
+
+// MergePaths computes the path of the resolution of a reference whose path is
+// relPath against a base whose path is basePath, as done by Resolve
+// (RFC 3986, Section 5.2.2), without the other components. hasAuthority
+// tells whether the base has an authority, in which case an empty base path
+// is merged as "/" (RFC 3986, Section 5.2.3).
+//
+// An absolute relPath replaces the base path, and any other non-empty relPath
+// is appended to all but the last segment of the base path. In both cases,
+// the dot-segments of the result are removed as NormalizePath does. An empty
+// relPath leaves the base path unchanged.
+func MergePaths(basePath, relPath string, hasAuthority bool) string {
+	if relPath == "" {
+		return basePath
+	}
+	path, _ := mergePathsChecked(basePath, relPath, hasAuthority)
+	return path
+}
+
+// mergePathsChecked is like MergePaths for a non-empty relPath but also
+// reports whether the merged path tried to go above its root, as
+// removeDotSegmentsChecked does.
+func mergePathsChecked(basePath, relPath string, hasAuthority bool) (string, bool) {
+	if strings.HasPrefix(relPath, "/") {
+		return removeDotSegmentsChecked(relPath)
+	}
+	if basePath == "" && hasAuthority {
+		basePath = "/"
+	}
+	return resolvePathChecked(basePath, relPath)
+}
+
+// mergeRelativePaths is like mergePathsChecked for a base that has neither a
+// scheme nor an authority, whose path may be rootless. The ".." segments of a
+// rootless merged path that cannot be cancelled are kept, as they would go
+// above the start of the base, which is not its root, so that resolving the
+// result against another base is the same as resolving both references in
+// turn.
+func mergeRelativePaths(basePath, relPath string) string {
+	merged := relPath
+	if lastSlash := strings.LastIndex(basePath, "/"); lastSlash >= 0 && !strings.HasPrefix(relPath, "/") {
+		merged = basePath[:lastSlash+1] + relPath
+	}
+	if strings.HasPrefix(merged, "/") {
+		return removeDotSegments(merged)
+	}
+	return removeRootlessDotSegments(merged)
+}
+
+// removeRootlessDotSegments removes the dot-segments of a rootless path, like
+// removeDotSegments, except that the leading ".." segments that cannot be
+// cancelled are kept: "a/../../b" gives "../b". A "./" is prepended when the
+// result would be empty or start with a slash, so that it still refers to the
+// same place, e.g., "a/.." gives "./" rather than "".
+func removeRootlessDotSegments(path string) string {
+	segments := strings.Split(path, "/")
+	output := make([]string, 0, len(segments))
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch {
+		case segment == ".":
+		case segment == ".." && len(output) > 0 && output[len(output)-1] != "..":
+			output = output[:len(output)-1]
+		default:
+			output = append(output, segment)
+			continue
+		}
+		// A trailing dot-segment leaves a trailing slash.
+		if last {
+			output = append(output, "")
+		}
+	}
+	if len(output) == 0 || output[0] == "" {
+		output = append([]string{"."}, output...)
 	}
-	return removeDotSegments(basePath[:lastSlash+1] + relPath)
+	return strings.Join(output, "/")
 }
@@ -242,16 +242,21 @@ func TestExtractFirstSegment(t *testing.T) {
 	}
 }
 
-// Tests for `removeDotSegments` are based on the examples from RFC 3986.
-func TestRemoveDotSegments(t *testing.T) {
+// removeDotSegmentsCase is a path with the result of the removal of its
+// dot-segments.
+type removeDotSegmentsCase struct {
+	name     string
+	input    string
+	expected string
+}
+
+// removeDotSegmentsCases returns the cases shared by the tests of
+// removeDotSegments and NormalizePath, based on the examples from RFC 3986.
+func removeDotSegmentsCases() []removeDotSegmentsCase {
 	// The base path directory used in many RFC 3986 examples for merging.
 	basePathDir := "/a/b/c/"
 
-	testCases := []struct {
-		name     string
-		input    string
-		expected string
-	}{
+	return []removeDotSegmentsCase{
 		// RFC 3986 Section 5.2.4 Examples
 		{"RFC 5.2.4 Ex1", "/a/b/c/./../../g", "/a/g"},
 		{"RFC 5.2.4 Ex2", "mid/content=5/../6", "mid/6"},
@@ -295,8 +300,11 @@ func TestRemoveDotSegments(t *testing.T) {
 		{"Trailing dot", "/a/b/.", "/a/b/"},
 		{"Trailing dots", "/a/b/..", "/a/"},
 	}
+}
 
-	for _, tc := range testCases {
+// Tests for `removeDotSegments` are based on the examples from RFC 3986.
+func TestRemoveDotSegments(t *testing.T) {
+	for _, tc := range removeDotSegmentsCases() {
 		t.Run(tc.name, func(t *testing.T) {
 			if got := removeDotSegments(tc.input); got != tc.expected {
 				t.Errorf("removeDotSegments(%q) = %q, want %q", tc.input, got, tc.expected)
@@ -305,6 +313,51 @@ func TestRemoveDotSegments(t *testing.T) {
 	}
 }
 
+// TestNormalizePath tests that the public NormalizePath has the behavior of
+// removeDotSegments.
+func TestNormalizePath(t *testing.T) {
+	for _, tc := range removeDotSegmentsCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NormalizePath(tc.input); got != tc.expected {
+				t.Errorf("NormalizePath(%q) = %q, want %q", tc.input, got, tc.expected)
+			}
+		})
+	}
+}
+
+// TestRemoveDotSegmentsChecked tests the detection of ".." segments going
+// above the root of an absolute path, using the abnormal examples of
+// RFC 3986, Section 5.4.2.
+func TestRemoveDotSegmentsChecked(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		expected  string
+		aboveRoot bool
+	}{
+		{"Within the path", "/a/b/../c", "/a/c", false},
+		{"Back to the root", "/a/b/../../g", "/g", false},
+		{"RFC 5.4.2 ../../../g", "/b/c/../../../g", "/g", true},
+		{"RFC 5.4.2 /../g", "/../g", "/g", true},
+		{"Root traversal", "/..", "/", true},
+		{"Single dot at the root", "/./g", "/g", false},
+		{"Escape then descend", "/a/../../b/c", "/b/c", true},
+		{"Dot-dot as part of a segment", "/..g", "/..g", false},
+		{"Relative path", "a/../b", "b", false},
+		{"Empty path", "", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, aboveRoot := removeDotSegmentsChecked(tc.input)
+			if got != tc.expected || aboveRoot != tc.aboveRoot {
+				t.Errorf("removeDotSegmentsChecked(%q) = (%q, %v), want (%q, %v)",
+					tc.input, got, aboveRoot, tc.expected, tc.aboveRoot)
+			}
+		})
+	}
+}
+
 // Tests for `resolvePath` are based on RFC 3986, Section 5.2.3, "Merge Paths".
 // `resolvePath` implements the second bullet point of this section.
 func TestResolvePath(t *testing.T) {
@@ -386,3 +439,82 @@ func TestResolvePath(t *testing.T) {
 		})
 	}
 }
+
+// TestMergePaths tests merging paths as done by the resolution of a relative
+// reference, including bases with empty segments such as "fred:///s//a/b/c".
+func TestMergePaths(t *testing.T) {
+	testCases := []struct {
+		name         string
+		basePath     string
+		relPath      string
+		hasAuthority bool
+		expected     string
+	}{
+		{"RFC merge", "/b/c/d;p", "g", true, "/b/c/g"},
+		{"RFC merge with up-directory", "/b/c/d;p", "../../g", true, "/g"},
+		{"Absolute relative path", "/b/c/d;p", "/./g/../h", true, "/h"},
+		{"Empty relative path", "/b/c/d;p", "", true, "/b/c/d;p"},
+		{"Empty base with authority", "", "g", true, "/g"},
+		{"Empty base without authority", "", "g", false, "g"},
+		{"Rootless base", "a/b", "../c", false, "c"},
+		{"Empty segments ../g", "/s//a/b/c", "../g", true, "/s//a/g"},
+		{"Empty segments ../../g", "/s//a/b/c", "../../g", true, "/s//g"},
+		{"Empty segments ../../../g", "/s//a/b/c", "../../../g", true, "/s/g"},
+		{"Empty segments ../../../../g", "/s//a/b/c", "../../../../g", true, "/g"},
+		{"Empty segments above root", "/s//a/b/c", "../../../../../g", true, "/g"},
+		{"Empty segments without authority", "/s//a/b/c", "../../g", false, "/s//g"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := MergePaths(tc.basePath, tc.relPath, tc.hasAuthority)
+			if got != tc.expected {
+				t.Errorf("MergePaths(%q, %q, %v) = %q, want %q",
+					tc.basePath, tc.relPath, tc.hasAuthority, got, tc.expected)
+			}
+		})
+	}
+
+	// The merged path must be the path of the resolved reference.
+	base := mustParseIri(t, "fred:///s//a/b/c")
+	for _, rel := range []string{"g", "../g", "../../g", "../../../g", "../../../../g", "./g/."} {
+		resolved, err := base.Resolve(rel)
+		if err != nil {
+			t.Fatalf("Resolve(%q) returned an unexpected error: %v", rel, err)
+		}
+		if got, want := MergePaths("/s//a/b/c", rel, true), resolved.Path(); got != want {
+			t.Errorf("MergePaths(%q) = %q, want the resolved path %q", rel, got, want)
+		}
+	}
+}
+
+// TestMergeRelativePaths tests merging paths against a base without a scheme
+// nor an authority, whose uncancelled ".." segments must be kept.
+func TestMergeRelativePaths(t *testing.T) {
+	testCases := []struct {
+		name     string
+		basePath string
+		relPath  string
+		expected string
+	}{
+		{"Simple merge", "a/b", "c", "a/c"},
+		{"Cancelled up-directory", "a/b/c", "../d", "a/d"},
+		{"Uncancelled up-directory", "a/b", "../../c", "../c"},
+		{"Up-directory in the base", "../a/b", "../../c", "../../c"},
+		{"Mixed dot-segments", "a/./b/", "./../../../c/.", "../c/"},
+		{"Trailing up-directory", "a/b/c", "..", "a/"},
+		{"Back to the start", "a/b", "..", "./"},
+		{"Single dot", "a", ".", "./"},
+		{"Empty base", "", "../c", "../c"},
+		{"Absolute base", "/a/b", "../../c", "/c"},
+		{"Absolute relative path", "a/b", "/../c", "/c"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := mergeRelativePaths(tc.basePath, tc.relPath); got != tc.expected {
+				t.Errorf("mergeRelativePaths(%q, %q) = %q, want %q", tc.basePath, tc.relPath, got, tc.expected)
+			}
+		})
+	}
+}
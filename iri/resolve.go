@@ -16,7 +16,10 @@ limitations under the License.
 
 package iri
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // resolvedIRI holds the components of an IRI after reference resolution.
 type resolvedIRI struct {
@@ -104,18 +107,17 @@ func (p *iriParser) resolvePathAndQuery(
 	rPath, rQuery string,
 	rHasQuery bool,
 	basePath, baseQuery string,
-	hasBaseQuery, hasBaseAuthority bool,
+	hasBaseQuery, hasBaseAuthority, hasBaseScheme bool,
 ) {
+	if rPath != "" && !hasBaseScheme && !hasBaseAuthority {
+		// The base is itself relative, as with Ref.ResolveAgainst.
+		t.Path = mergeRelativePaths(basePath, rPath)
+		t.Query = rQuery
+		t.HasQuery = rHasQuery
+		return
+	}
 	if rPath != "" {
-		if strings.HasPrefix(rPath, "/") {
-			t.Path = removeDotSegments(rPath)
-		} else {
-			mergePath := basePath
-			if mergePath == "" && hasBaseAuthority {
-				mergePath = "/"
-			}
-			t.Path = resolvePath(mergePath, rPath)
-		}
+		t.Path, p.pathAboveRoot = mergePathsChecked(basePath, rPath, hasBaseAuthority)
 		t.Query = rQuery
 		t.HasQuery = rHasQuery
 		return
@@ -160,42 +162,65 @@ func (p *iriParser) resolveComponents(relativeRef string) *resolvedIRI {
 	if rHasAuthority {
 		t.Authority = rAuthority
 		t.HasAuthority = true
-		t.Path = removeDotSegments(rPath)
+		t.Path, p.pathAboveRoot = removeDotSegmentsChecked(rPath)
 		t.Query = rQuery
 		t.HasQuery = rHasQuery
 	} else {
-		p.resolvePathAndQuery(t, rPath, rQuery, rHasQuery, basePath, baseQuery, hasBaseQuery, hasBaseAuthority)
+		p.resolvePathAndQuery(
+			t, rPath, rQuery, rHasQuery, basePath, baseQuery, hasBaseQuery, hasBaseAuthority, baseScheme != "",
+		)
 		t.Authority = baseAuthority
 		t.HasAuthority = hasBaseAuthority
 	}
 	return t
 }
 
+// baseComponents holds the components of a base IRI, as extracted by
+// getBaseComponents.
+type baseComponents struct {
+	scheme       string
+	authority    string
+	path         string
+	query        string
+	hasAuthority bool
+	hasQuery     bool
+}
+
 // getBaseComponents extracts the components from the base IRI for resolution.
+// If they were extracted beforehand, as is done when resolving a batch of
+// references, the cached components are returned instead.
 func (p *iriParser) getBaseComponents() (string, string, string, bool, string, bool) {
-	var scheme, authority, path, query string
-	var hasAuthority, hasQuery bool
-	base := p.base
+	c := p.base.components
+	if c == nil {
+		extracted := p.base.extractComponents()
+		c = &extracted
+	}
+	return c.scheme, c.authority, c.path, c.hasAuthority, c.query, c.hasQuery
+}
+
+// extractComponents slices the components of the base IRI out of its string.
+func (b *iriParserBase) extractComponents() baseComponents {
+	var c baseComponents
 
-	if base.schemeEnd > 0 {
-		scheme = base.iri[:base.schemeEnd-1]
+	if b.schemeEnd > 0 {
+		c.scheme = b.iri[:b.schemeEnd-1]
 	}
-	if base.authorityEnd > base.schemeEnd {
-		hasAuthority = true
-		start := base.schemeEnd
-		if strings.HasPrefix(base.iri[start:], "//") {
+	if b.authorityEnd > b.schemeEnd {
+		c.hasAuthority = true
+		start := b.schemeEnd
+		if strings.HasPrefix(b.iri[start:], "//") {
 			start += 2
 		}
-		if base.authorityEnd > start {
-			authority = base.iri[start:base.authorityEnd]
+		if b.authorityEnd > start {
+			c.authority = b.iri[start:b.authorityEnd]
 		}
 	}
-	path = base.iri[base.authorityEnd:base.pathEnd]
-	if base.queryEnd > base.pathEnd {
-		query = base.iri[base.pathEnd+1 : base.queryEnd]
-		hasQuery = true
+	c.path = b.iri[b.authorityEnd:b.pathEnd]
+	if b.queryEnd > b.pathEnd {
+		c.query = b.iri[b.pathEnd+1 : b.queryEnd]
+		c.hasQuery = true
 	}
-	return scheme, authority, path, hasAuthority, query, hasQuery
+	return c
 }
 
 // recomposeIRI assembles the final IRI from its resolved components into the output buffer.
@@ -208,7 +233,17 @@ func (p *iriParser) recomposeIRI(t *resolvedIRI) {
 
 	if t.HasAuthority {
 		p.output.writeString("//")
+		authorityStart := p.output.len()
 		p.output.writeString(t.Authority)
+
+		// The authority has already been validated, so its subcomponent
+		// boundaries can be derived from a split instead of a full parse.
+		userinfo, host, _ := splitAuthority(t.Authority)
+		p.outputUserInfoEnd = authorityStart
+		if strings.Contains(t.Authority, "@") {
+			p.outputUserInfoEnd += len(userinfo) + 1
+		}
+		p.outputHostEnd = p.outputUserInfoEnd + len(host)
 	}
 	p.outputPositions.AuthorityEnd = p.output.len()
 
@@ -244,11 +279,12 @@ func (p *iriParser) parseRelativeNoBase() error {
 // validateRelativeRef runs a sub-parse on the relative reference string to ensure it's well-formed.
 func (p *iriParser) validateRelativeRef(relativeRef string) error {
 	validationParser := &iriParser{
-		iri:       relativeRef,
-		base:      &iriParserBase{hasBase: false},
-		input:     newParserInput(relativeRef),
-		output:    &voidOutputBuffer{},
-		unchecked: false,
+		iri:         relativeRef,
+		base:        &iriParserBase{hasBase: false},
+		input:       newParserInput(relativeRef),
+		output:      &voidOutputBuffer{},
+		unchecked:   false,
+		inputOffset: p.inputOffset,
 	}
 	if err := validationParser.parseSchemeStart(); err != nil {
 		return err
@@ -268,7 +304,10 @@ func (p *iriParser) validateRelativeRef(relativeRef string) error {
 		if !strings.HasPrefix(uriAfterScheme, "/") {
 			// This is the ambiguous case (e.g., "a:b"). Per RFC 3986, this form
 			// is invalid as a relative-path reference.
-			return &kindError{message: "Invalid IRI character in first path segment", char: ':'}
+			return validationParser.errorAt(
+				validationParser.inputSchemeEnd-1,
+				&kindError{message: "Invalid IRI character in first path segment", char: ':'},
+			)
 		}
 	}
 
@@ -284,12 +323,27 @@ func (p *iriParser) parseRelative() error {
 	}
 
 	relativeRef := p.input.asStr()
-	if err := p.validateRelativeRef(relativeRef); err != nil {
-		return err
+	if !p.relativeValidated {
+		if err := p.validateRelativeRef(relativeRef); err != nil {
+			return err
+		}
 	}
 
 	t := p.resolveComponents(relativeRef)
 
+	if p.strictDotSegments && p.pathAboveRoot {
+		return fmt.Errorf("%w: %s", ErrPathAboveRoot, relativeRef)
+	}
+
+	// RFC 3986, Section 3.3: the merged path must not start with "//" if there
+	// is no authority, as it would then be read as one. The validation
+	// sub-parse already rejects such references, so this is only checked when
+	// it is skipped. The "//" only appears once merged, so the error points at
+	// the start of the reference.
+	if p.relativeValidated && !t.HasAuthority && strings.HasPrefix(t.Path, "//") {
+		return p.errorAt(p.input.position(), errPathStartingWithSlashes)
+	}
+
 	p.recomposeIRI(t)
 	return nil
 }
@@ -18,6 +18,7 @@ limitations under the License.
 package iri
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -196,6 +197,15 @@ func TestGetBaseComponents(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("Cached components", func(t *testing.T) {
+		p := newTestParserWithBase(t, "http://a/b?c")
+		p.base.components = &baseComponents{scheme: "s", path: "/cached"}
+		scheme, _, path, hasAuthority, _, _ := p.getBaseComponents()
+		if scheme != "s" || path != "/cached" || hasAuthority {
+			t.Errorf("getBaseComponents() did not return the cached components: %q, %q, %v", scheme, path, hasAuthority)
+		}
+	})
 }
 
 // TestResolvePathAndQuery tests the path and query resolution logic from RFC 3986, Section 5.2.2.
@@ -211,17 +221,19 @@ func TestResolvePathAndQuery(t *testing.T) {
 		baseQuery        string
 		hasBaseQuery     bool
 		hasBaseAuthority bool
+		hasBaseScheme    bool
 		wantPath         string
 		wantQuery        string
 		wantHasQuery     bool
 	}{
-		{"Ref path is absolute", "/g", "y", true, "/a/b", "x", true, true, "/g", "y", true},
-		{"Ref path is relative", "g", "y", true, "/a/b", "x", true, true, "/a/g", "y", true},
-		{"Base has authority, no path", "g", "", false, "", "x", true, true, "/g", "", false},
-		{"Ref path is empty, ref has query", "", "y", true, "/a/b", "x", true, true, "/a/b", "y", true},
-		{"Ref path is empty, ref has no query", "", "", false, "/a/b", "x", true, true, "/a/b", "x", true},
-		{"Ref path empty, no queries", "", "", false, "/a/b", "", false, true, "/a/b", "", false},
-		{"Ref path is empty, base has empty query", "", "", false, "/a/b", "", true, true, "/a/b", "", true},
+		{"Ref path is absolute", "/g", "y", true, "/a/b", "x", true, true, true, "/g", "y", true},
+		{"Ref path is relative", "g", "y", true, "/a/b", "x", true, true, true, "/a/g", "y", true},
+		{"Base has authority, no path", "g", "", false, "", "x", true, true, true, "/g", "", false},
+		{"Ref path is empty, ref has query", "", "y", true, "/a/b", "x", true, true, true, "/a/b", "y", true},
+		{"Ref path is empty, ref has no query", "", "", false, "/a/b", "x", true, true, true, "/a/b", "x", true},
+		{"Ref path empty, no queries", "", "", false, "/a/b", "", false, true, true, "/a/b", "", false},
+		{"Ref path is empty, base has empty query", "", "", false, "/a/b", "", true, true, true, "/a/b", "", true},
+		{"Base is relative", "../../g", "", false, "a/b", "", false, false, false, "../g", "", false},
 	}
 
 	for _, tt := range tests {
@@ -229,7 +241,7 @@ func TestResolvePathAndQuery(t *testing.T) {
 			target := &resolvedIRI{}
 			p.resolvePathAndQuery(
 				target, tt.rPath, tt.rQuery, tt.rHasQuery,
-				tt.basePath, tt.baseQuery, tt.hasBaseQuery, tt.hasBaseAuthority,
+				tt.basePath, tt.baseQuery, tt.hasBaseQuery, tt.hasBaseAuthority, tt.hasBaseScheme,
 			)
 			if target.Path != tt.wantPath {
 				t.Errorf("resolvePathAndQuery() path = %q, want %q", target.Path, tt.wantPath)
@@ -479,3 +491,104 @@ func TestRecomposeNormalizedIRI(t *testing.T) {
 		})
 	}
 }
+
+// TestResolve_EmptyQueryAndFragment checks that an empty query or fragment in
+// the relative reference is kept in the result, as it is distinct from an
+// absent one (RFC 3986, Section 5.2.2, where "defined" is not "non-empty").
+func TestResolve_EmptyQueryAndFragment(t *testing.T) {
+	tests := []struct {
+		name         string
+		base         string
+		relativeRef  string
+		want         string
+		wantQuery    string
+		wantHasQuery bool
+		wantHasFrag  bool
+	}{
+		{"Empty fragment", "http://a/b?q", "#", "http://a/b?q#", "q", true, true},
+		{"Empty fragment replaces base fragment", "http://a/b?q#x", "#", "http://a/b?q#", "q", true, true},
+		{"Empty query", "http://a/b?q", "?", "http://a/b?", "", true, false},
+		{"Empty query and fragment", "http://a/b?q#x", "?#", "http://a/b?#", "", true, true},
+		{"Empty reference", "http://a/b?q#x", "", "http://a/b?q", "q", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestParserWithBase(t, tt.base).resolveComponents(tt.relativeRef)
+			if result.HasQuery != tt.wantHasQuery || result.HasFragment != tt.wantHasFrag {
+				t.Errorf("resolveComponents(%q) HasQuery = %v, HasFragment = %v, want %v, %v",
+					tt.relativeRef, result.HasQuery, result.HasFragment, tt.wantHasQuery, tt.wantHasFrag)
+			}
+
+			base, err := ParseRef(tt.base)
+			if err != nil {
+				t.Fatalf("ParseRef(%q) failed: %v", tt.base, err)
+			}
+			got, err := base.Resolve(tt.relativeRef)
+			if err != nil {
+				t.Fatalf("Resolve(%q) failed: %v", tt.relativeRef, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Resolve(%q) = %q, want %q", tt.relativeRef, got.String(), tt.want)
+			}
+			if query, ok := got.Query(); query != tt.wantQuery || ok != tt.wantHasQuery {
+				t.Errorf("Query() = (%q, %v), want (%q, %v)", query, ok, tt.wantQuery, tt.wantHasQuery)
+			}
+			if fragment, ok := got.Fragment(); fragment != "" || ok != tt.wantHasFrag {
+				t.Errorf("Fragment() = (%q, %v), want (%q, %v)", fragment, ok, "", tt.wantHasFrag)
+			}
+
+			var b strings.Builder
+			if _, err = base.ResolveTo(tt.relativeRef, &b); err != nil || b.String() != tt.want {
+				t.Errorf("ResolveTo(%q) = (%q, %v), want %q", tt.relativeRef, b.String(), err, tt.want)
+			}
+		})
+	}
+}
+
+// TestResolve_MergedPathStartingWithSlashes checks that a reference whose
+// merged path would start with "//" against a base without an authority, and
+// would then read as a network-path reference, is rejected by Resolve with an
+// offset, while the check of the merged path after resolution is only done for
+// references that skip validation, as with ResolveRef.
+func TestResolve_MergedPathStartingWithSlashes(t *testing.T) {
+	tests := []struct {
+		base        string
+		relativeRef string
+		wantOffset  int
+	}{
+		{"a:/b", ".//c", 2},
+		{"a:/b/c", "..//d", 3},
+		{"a:b", "/.//c", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.base+" + "+tt.relativeRef, func(t *testing.T) {
+			base, err := ParseRef(tt.base)
+			if err != nil {
+				t.Fatalf("ParseRef(%q) failed: %v", tt.base, err)
+			}
+			got, err := base.Resolve(tt.relativeRef)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) || parseErr.Message != errPathStartingWithSlashes.Error() {
+				t.Fatalf("Resolve(%q) = %v, %v, want a path starting with slashes error", tt.relativeRef, got, err)
+			}
+			if parseErr.Offset != tt.wantOffset {
+				t.Errorf("Resolve(%q) error offset = %d, want %d", tt.relativeRef, parseErr.Offset, tt.wantOffset)
+			}
+
+			// Without validation, the merged path is checked instead.
+			p := newTestParserWithBase(t, tt.base)
+			p.input = newParserInput(tt.relativeRef)
+			p.output = &stringOutputBuffer{builder: &strings.Builder{}}
+			p.relativeValidated = true
+			var oe *offsetError
+			if err = p.parseRelative(); !errors.As(err, &oe) || !errors.Is(err, errPathStartingWithSlashes) {
+				t.Fatalf("parseRelative(%q) error = %v, want a path starting with slashes error", tt.relativeRef, err)
+			}
+			if oe.offset != 0 {
+				t.Errorf("parseRelative(%q) error offset = %d, want 0", tt.relativeRef, oe.offset)
+			}
+		})
+	}
+}
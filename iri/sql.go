@@ -0,0 +1,58 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iri
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Scan implements the sql.Scanner interface so that an Iri can be read
+// directly from a database text column. It accepts string and []byte values,
+// which are parsed with ParseIri semantics, and nil (SQL NULL), which leaves
+// the Iri as its zero value. An empty string is not an absolute IRI and
+// therefore results in a *ParseError rather than a zero Iri.
+func (i *Iri) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*i = Iri{}
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan value of type %T into an Iri", src)
+	}
+
+	newIri, err := ParseIri(s)
+	if err != nil {
+		return err
+	}
+	*i = *newIri
+	return nil
+}
+
+// Value implements the driver.Valuer interface, returning the IRI as a string.
+// The zero Iri is stored as SQL NULL.
+func (i Iri) Value() (driver.Value, error) {
+	if i.iri == "" {
+		return nil, nil //nolint:nilnil // A nil value is how SQL NULL is represented.
+	}
+	return i.iri, nil
+}
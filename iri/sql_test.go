@@ -0,0 +1,90 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//nolint:testpackage // This is a white-box test file for an internal package. It needs to be in the same package to test unexported functions.
+package iri
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+// Compile-time checks that Iri implements the database/sql interfaces.
+var (
+	_ sql.Scanner   = (*Iri)(nil)
+	_ driver.Valuer = Iri{}
+)
+
+// TestIri_Scan tests reading an Iri from the values a database driver may return.
+func TestIri_Scan(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      any
+		expected string
+		wantErr  bool
+	}{
+		{name: "String", src: "http://example.com/a", expected: "http://example.com/a"},
+		{name: "Bytes", src: []byte("urn:isbn:0451450523"), expected: "urn:isbn:0451450523"},
+		{name: "Nil", src: nil, expected: ""},
+		{name: "Empty string", src: "", wantErr: true},
+		{name: "Relative IRI", src: "/a/b", wantErr: true},
+		{name: "Malformed IRI", src: "http://example.com/[", wantErr: true},
+		{name: "Unsupported type", src: 42, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			i := *mustParseIri(t, "http://previous.example/")
+			err := i.Scan(tc.src)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if i.String() != tc.expected {
+				t.Errorf("Scan() produced '%s', want '%s'", i.String(), tc.expected)
+			}
+		})
+	}
+
+	t.Run("Malformed data is a ParseError", func(t *testing.T) {
+		var i Iri
+		var parseErr *ParseError
+		if err := i.Scan("http://example.com/["); !errors.As(err, &parseErr) {
+			t.Errorf("Expected a *ParseError, got %v", err)
+		}
+	})
+}
+
+// TestIri_Value tests converting an Iri into a database value.
+func TestIri_Value(t *testing.T) {
+	i := mustParseIri(t, "http://example.com/a")
+	v, err := i.Value()
+	if err != nil {
+		t.Fatalf("Value() returned an unexpected error: %v", err)
+	}
+	if v != "http://example.com/a" {
+		t.Errorf("Value() = %v, want %q", v, "http://example.com/a")
+	}
+
+	v, err = Iri{}.Value()
+	if err != nil || v != nil {
+		t.Errorf("Value() of the zero Iri = (%v, %v), want (nil, nil)", v, err)
+	}
+}
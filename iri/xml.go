@@ -0,0 +1,48 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iri
+
+import "encoding/xml"
+
+// MarshalXML implements the xml.Marshaler interface, encoding the IRI as the
+// character data of the element.
+func (i *Iri) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(i.iri, start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface. Like UnmarshalJSON,
+// it validates the character data of the element with ParseIri, so a relative
+// reference results in a *ParseError.
+func (i *Iri) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	return i.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface, so that an Iri
+// can be used as an attribute such as an Atom href.
+func (i *Iri) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: i.iri}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface. The attribute
+// value must be an absolute IRI, as with UnmarshalXML.
+func (i *Iri) UnmarshalXMLAttr(attr xml.Attr) error {
+	return i.UnmarshalText([]byte(attr.Value))
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iri
+
+import (
+	"encoding/xml"
+	"errors"
+	"testing"
+)
+
+// Compile-time checks that Iri implements the encoding/xml interfaces.
+var (
+	_ xml.Marshaler       = (*Iri)(nil)
+	_ xml.Unmarshaler     = (*Iri)(nil)
+	_ xml.MarshalerAttr   = (*Iri)(nil)
+	_ xml.UnmarshalerAttr = (*Iri)(nil)
+)
+
+// xmlLink mimics an Atom link, with the IRI both as an attribute and as an
+// element.
+type xmlLink struct {
+	XMLName xml.Name `xml:"link"`
+	Href    Iri      `xml:"href,attr"`
+	ID      Iri      `xml:"id"`
+}
+
+// TestIri_XML tests encoding an Iri as an XML attribute and element, and
+// decoding it back.
+func TestIri_XML(t *testing.T) {
+	in := xmlLink{
+		Href: *mustParseIri(t, "http://example.com/résumé?a=1&b=2"),
+		ID:   *mustParseIri(t, "urn:uuid:60a76c80-d399-11d9-b93C-0003939e0af6"),
+	}
+	data, err := xml.Marshal(&in)
+	if err != nil {
+		t.Fatalf("xml.Marshal failed: %v", err)
+	}
+	want := `<link href="http://example.com/résumé?a=1&amp;b=2">` +
+		`<id>urn:uuid:60a76c80-d399-11d9-b93C-0003939e0af6</id></link>`
+	if string(data) != want {
+		t.Errorf("xml.Marshal produced %s, want %s", data, want)
+	}
+
+	var out xmlLink
+	if err = xml.Unmarshal(data, &out); err != nil {
+		t.Fatalf("xml.Unmarshal failed: %v", err)
+	}
+	if !out.Href.Equal(&in.Href) || !out.ID.Equal(&in.ID) {
+		t.Errorf("Round trip produced %+v, want %+v", out, in)
+	}
+}
+
+// TestIri_XML_Invalid tests that decoding rejects relative and malformed IRIs.
+func TestIri_XML_Invalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{name: "Relative attribute", data: `<link href="/a/b"><id>urn:a</id></link>`},
+		{name: "Malformed attribute", data: `<link href="http://example.com/["><id>urn:a</id></link>`},
+		{name: "Relative element", data: `<link href="urn:a"><id>/a/b</id></link>`},
+		{name: "Empty element", data: `<link href="urn:a"><id></id></link>`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out xmlLink
+			err := xml.Unmarshal([]byte(tc.data), &out)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Errorf("Expected a *ParseError, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Errors that can occur during Accept-Language header parsing.
+var (
+	ErrInvalidQuality     = errors.New("the quality value must be a number between 0 and 1")
+	ErrInvalidAcceptEntry = errors.New("an Accept-Language entry is malformed")
+)
+
+// WeightedTag is a language range of an Accept-Language header together with
+// its quality value.
+type WeightedTag struct {
+	Tag     LanguageTag
+	Quality float64
+}
+
+// ParseAcceptLanguage parses the value of an HTTP Accept-Language header, as
+// defined in RFC 9110, Section 12.5.4, into a preference list sorted by
+// descending quality. Entries with the same quality keep their header order.
+// An entry without a quality value has a quality of 1, and the range "*" is
+// returned as Wildcard(). Entries with a quality of 0, which mark a language
+// as not acceptable, are kept at the end of the list.
+//
+// Parsing is lenient: malformed entries, such as an ill-formed tag or a
+// quality value outside of [0, 1], are skipped. An error is only returned if
+// the header is not empty and none of its entries could be parsed. Use
+// ParseAcceptLanguageStrict to reject the whole header instead.
+func (p *Parser) ParseAcceptLanguage(header string) ([]WeightedTag, error) {
+	return p.parseAcceptLanguage(header, false)
+}
+
+// ParseAcceptLanguageStrict is like ParseAcceptLanguage, but it returns an
+// error as soon as an entry of the header is malformed.
+func (p *Parser) ParseAcceptLanguageStrict(header string) ([]WeightedTag, error) {
+	return p.parseAcceptLanguage(header, true)
+}
+
+// parseAcceptLanguage implements ParseAcceptLanguage and
+// ParseAcceptLanguageStrict.
+func (p *Parser) parseAcceptLanguage(header string, strict bool) ([]WeightedTag, error) {
+	var tags []WeightedTag
+	var firstErr error
+	for entry := range strings.SplitSeq(header, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			// RFC 9110, Section 5.6.1 requires empty list elements to be ignored.
+			continue
+		}
+		wt, err := p.parseAcceptEntry(entry, strict)
+		if err != nil {
+			if strict {
+				return nil, err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		tags = append(tags, wt)
+	}
+
+	if len(tags) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	slices.SortStableFunc(tags, func(a, b WeightedTag) int {
+		return cmp.Compare(b.Quality, a.Quality)
+	})
+	return tags, nil
+}
+
+// parseAcceptEntry parses a single language range of an Accept-Language
+// header, with its optional weight (e.g., "en-US;q=0.8"). In strict mode, the
+// weight must follow the qvalue grammar.
+func (p *Parser) parseAcceptEntry(entry string, strict bool) (WeightedTag, error) {
+	tagStr, params, hasParams := strings.Cut(entry, ";")
+	tagStr = strings.TrimSpace(tagStr)
+
+	wt := WeightedTag{Quality: 1}
+	if tagStr == wildcardRange {
+		wt.Tag = Wildcard()
+	} else {
+		lt, err := p.Parse(tagStr)
+		if err != nil {
+			return WeightedTag{}, fmt.Errorf("%w: '%s': %w", ErrInvalidAcceptEntry, entry, err)
+		}
+		wt.Tag = lt
+	}
+
+	if !hasParams {
+		return wt, nil
+	}
+	name, value, ok := strings.Cut(strings.TrimSpace(params), "=")
+	if !ok || !strings.EqualFold(strings.TrimSpace(name), "q") {
+		return WeightedTag{}, fmt.Errorf("%w: '%s': expected a quality value", ErrInvalidAcceptEntry, entry)
+	}
+	q, err := parseQuality(strings.TrimSpace(value), strict)
+	if err != nil {
+		return WeightedTag{}, fmt.Errorf("%w: '%s': %w", ErrInvalidAcceptEntry, entry, err)
+	}
+	wt.Quality = q
+	return wt, nil
+}
+
+// parseQuality parses a quality value and checks that it lies within [0, 1].
+// In strict mode, it must also match the qvalue grammar, which rejects the
+// other forms accepted by strconv.ParseFloat, such as "1e-1" or ".5".
+func parseQuality(s string, strict bool) (float64, error) {
+	if strict && !isQValue(s) {
+		return 0, fmt.Errorf("%w: '%s'", ErrInvalidQuality, s)
+	}
+	q, err := strconv.ParseFloat(s, 64)
+	if err != nil || !(q >= 0 && q <= 1) {
+		return 0, fmt.Errorf("%w: '%s'", ErrInvalidQuality, s)
+	}
+	return q, nil
+}
+
+// isQValue reports whether s matches the qvalue grammar of RFC 9110,
+// Section 12.4.2: a "0" or "1", optionally followed by a dot and at most three
+// digits, which must all be "0" after a "1".
+func isQValue(s string) bool {
+	if s == "" || (s[0] != '0' && s[0] != '1') {
+		return false
+	}
+	fraction, hasFraction := strings.CutPrefix(s[1:], ".")
+	if !hasFraction {
+		return s[1:] == ""
+	}
+	if len(fraction) > len("000") {
+		return false
+	}
+	for i := range len(fraction) {
+		if !isDigit(fraction[i]) || (s[0] == '1' && fraction[i] != '0') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParser_ParseAcceptLanguage tests the lenient parsing of Accept-Language
+// header values, as defined in RFC 9110, Section 12.5.4.
+func TestParser_ParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    []WeightedTag
+		wantErr error
+	}{
+		{
+			name:   "RFC 9110 example",
+			header: "da, en-gb;q=0.8, en;q=0.7",
+			want: []WeightedTag{
+				{Tag: mustParse(t, "da"), Quality: 1},
+				{Tag: mustParse(t, "en-GB"), Quality: 0.8},
+				{Tag: mustParse(t, "en"), Quality: 0.7},
+			},
+		},
+		{
+			name:   "Sorted by descending quality with stable ties",
+			header: "fr;q=0.5,de,en;q=0.9,it;q=0.5,es",
+			want: []WeightedTag{
+				{Tag: mustParse(t, "de"), Quality: 1},
+				{Tag: mustParse(t, "es"), Quality: 1},
+				{Tag: mustParse(t, "en"), Quality: 0.9},
+				{Tag: mustParse(t, "fr"), Quality: 0.5},
+				{Tag: mustParse(t, "it"), Quality: 0.5},
+			},
+		},
+		{
+			name:   "Wildcard, spaces and uppercase parameter",
+			header: " * ; Q=0.1 ,, en ;q= 1.000 ",
+			want: []WeightedTag{
+				{Tag: mustParse(t, "en"), Quality: 1},
+				{Tag: Wildcard(), Quality: 0.1},
+			},
+		},
+		{
+			name:   "Not acceptable languages are kept",
+			header: "en;q=0,fr",
+			want: []WeightedTag{
+				{Tag: mustParse(t, "fr"), Quality: 1},
+				{Tag: mustParse(t, "en"), Quality: 0},
+			},
+		},
+		{
+			name:   "Malformed entries are skipped",
+			header: "en_US, fr;q=1.5, de;q=abc, it;level=1, es;q=NaN, pt;q=0.3",
+			want: []WeightedTag{
+				{Tag: mustParse(t, "pt"), Quality: 0.3},
+			},
+		},
+		{
+			name:   "Empty header",
+			header: " , ",
+		},
+		{
+			name:    "Only malformed entries",
+			header:  "en_US, fr;q=2",
+			wantErr: ErrInvalidAcceptEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.ParseAcceptLanguage(tt.header)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseAcceptLanguage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assertWeightedTags(t, got, tt.want)
+		})
+	}
+}
+
+// TestParser_ParseAcceptLanguageStrict tests that the strict variant rejects
+// headers containing a malformed entry.
+func TestParser_ParseAcceptLanguageStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		wantErr error
+	}{
+		{name: "Ill-formed tag", header: "en, en_US", wantErr: ErrForbiddenChar},
+		{name: "Quality above 1", header: "en;q=1.5", wantErr: ErrInvalidQuality},
+		{name: "Negative quality", header: "en;q=-0.1", wantErr: ErrInvalidQuality},
+		{name: "Quality not a number", header: "en;q=high", wantErr: ErrInvalidQuality},
+		{name: "Unknown parameter", header: "en;level=1", wantErr: ErrInvalidAcceptEntry},
+		{name: "Missing tag", header: ";q=0.5", wantErr: ErrInvalidAcceptEntry},
+		{name: "Exponent", header: "en;q=1e-1", wantErr: ErrInvalidQuality},
+		{name: "Missing leading digit", header: "en;q=.5", wantErr: ErrInvalidQuality},
+		{name: "Explicit sign", header: "en;q=+0.5", wantErr: ErrInvalidQuality},
+		{name: "Hexadecimal float", header: "en;q=0x1p-1", wantErr: ErrInvalidQuality},
+		{name: "Too many decimals", header: "en;q=0.1234", wantErr: ErrInvalidQuality},
+		{name: "Non-zero decimal after 1", header: "en;q=1.001", wantErr: ErrInvalidQuality},
+		{name: "Leading zero", header: "en;q=00.5", wantErr: ErrInvalidQuality},
+		{name: "Second dot", header: "en;q=0.5.", wantErr: ErrInvalidQuality},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.ParseAcceptLanguageStrict(tt.header)
+			if !errors.Is(err, tt.wantErr) || !errors.Is(err, ErrInvalidAcceptEntry) {
+				t.Errorf("ParseAcceptLanguageStrict() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != nil {
+				t.Errorf("ParseAcceptLanguageStrict() = %v, want nil", got)
+			}
+		})
+	}
+
+	t.Run("Qvalue grammar", func(t *testing.T) {
+		for _, q := range []string{"0", "0.", "0.5", "0.123", "1", "1.", "1.000", "0.000"} {
+			if _, err := p.ParseAcceptLanguageStrict("en;q=" + q); err != nil {
+				t.Errorf("ParseAcceptLanguageStrict(%q) unexpected error: %v", "en;q="+q, err)
+			}
+		}
+		// The lenient parser keeps accepting any number within [0, 1].
+		if got, err := p.ParseAcceptLanguage("en;q=.5"); err != nil || len(got) != 1 || got[0].Quality != 0.5 {
+			t.Errorf("ParseAcceptLanguage(\"en;q=.5\") = %v, %v", got, err)
+		}
+	})
+
+	t.Run("Valid header", func(t *testing.T) {
+		got, err := p.ParseAcceptLanguageStrict("en;q=0.5, *")
+		if err != nil {
+			t.Fatalf("ParseAcceptLanguageStrict() unexpected error: %v", err)
+		}
+		assertWeightedTags(t, got, []WeightedTag{
+			{Tag: Wildcard(), Quality: 1},
+			{Tag: mustParse(t, "en"), Quality: 0.5},
+		})
+	})
+}
+
+// assertWeightedTags is a test helper that compares two preference lists.
+func assertWeightedTags(t *testing.T, got, want []WeightedTag) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d weighted tags %v, want %d", len(got), got, len(want))
+	}
+	for i := range got {
+		if got[i].Tag.String() != want[i].Tag.String() || got[i].Quality != want[i].Quality {
+			t.Errorf("weighted tag %d = (%q, %v), want (%q, %v)",
+				i, got[i].Tag.String(), got[i].Quality, want[i].Tag.String(), want[i].Quality)
+		}
+	}
+}
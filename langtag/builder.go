@@ -0,0 +1,173 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Errors that can occur when building a language tag.
+var (
+	ErrDuplicateLanguage = errors.New("the primary language subtag is set more than once")
+)
+
+// Builder assembles a language tag from its individual subtags. Each setter
+// returns the Builder so that calls can be chained, and Build renders the tag
+// with the canonical casing of RFC 5646 before validating it with Parse. The
+// zero value is an empty Builder ready to use.
+type Builder struct {
+	language      string
+	extlangs      []string
+	script        string
+	region        string
+	variants      []string
+	extensions    []Extension
+	privateuse    []string
+	languageCount int
+}
+
+// NewBuilder returns a new, empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Language sets the primary language subtag (e.g., "en"). Setting it more
+// than once makes Build fail with ErrDuplicateLanguage.
+func (b *Builder) Language(language string) *Builder {
+	b.language = language
+	b.languageCount++
+	return b
+}
+
+// Extlang adds an extended language subtag (e.g., "yue" in "zh-yue"). Adding
+// more than one makes Build fail with ErrTooManyExtlangs.
+func (b *Builder) Extlang(extlang string) *Builder {
+	b.extlangs = append(b.extlangs, extlang)
+	return b
+}
+
+// Script sets the script subtag (e.g., "Latn").
+func (b *Builder) Script(script string) *Builder {
+	b.script = script
+	return b
+}
+
+// Region sets the region subtag (e.g., "US" or "419").
+func (b *Builder) Region(region string) *Builder {
+	b.region = region
+	return b
+}
+
+// AddVariant appends a variant subtag (e.g., "1901").
+func (b *Builder) AddVariant(variant string) *Builder {
+	b.variants = append(b.variants, variant)
+	return b
+}
+
+// AddExtension appends an extension introduced by a singleton, with a value
+// made of one or more subtags (e.g., 'u' and "co-phonebk"). Extensions are
+// rendered in the order they were added.
+func (b *Builder) AddExtension(singleton rune, value string) *Builder {
+	b.extensions = append(b.extensions, Extension{Singleton: singleton, Value: value})
+	return b
+}
+
+// PrivateUse sets the private use subtags, rendered after the 'x' singleton.
+func (b *Builder) PrivateUse(subtags ...string) *Builder {
+	b.privateuse = slices.Clone(subtags)
+	return b
+}
+
+// Build renders the subtags and validates the result with p.Parse. Besides
+// the errors of Parse, it returns ErrDuplicateLanguage if the primary language
+// was set more than once, ErrTooManyExtlangs if more than one extlang was
+// added, and ErrInvalidSubtag if a subtag would not be read back at the
+// position it was given for (e.g., a region of four digits, which is a
+// variant).
+func (b *Builder) Build(p *Parser) (LanguageTag, error) {
+	if b.languageCount > 1 {
+		return LanguageTag{}, ErrDuplicateLanguage
+	}
+	if len(b.extlangs) > maxExtlangs {
+		return LanguageTag{}, ErrTooManyExtlangs
+	}
+	for _, ext := range b.extensions {
+		if ext.Singleton == 'x' || ext.Singleton == 'X' {
+			return LanguageTag{}, ErrInvalidSubtag
+		}
+	}
+
+	cpr := &canonicalParseRun{
+		parent:     p,
+		language:   b.language,
+		extlangs:   b.extlangs,
+		script:     b.script,
+		region:     b.region,
+		variants:   b.variants,
+		extensions: b.extensions,
+		privateuse: b.privateuse,
+	}
+	if len(b.privateuse) > 0 {
+		cpr.state = stateInPrivateUse
+	}
+	var sb strings.Builder
+	cpr.render(&sb)
+
+	lt, err := p.Parse(sb.String())
+	if err != nil {
+		return LanguageTag{}, err
+	}
+	if !b.matches(&lt) {
+		return LanguageTag{}, ErrInvalidSubtag
+	}
+	return lt, nil
+}
+
+// matches reports whether the subtags of a parsed tag are exactly the ones
+// that were set on the Builder.
+func (b *Builder) matches(lt *LanguageTag) bool {
+	script, _ := lt.Script()
+	region, _ := lt.Region()
+	sameExtension := func(got, want Extension) bool {
+		return got.Singleton == unicode.ToLower(want.Singleton) && strings.EqualFold(got.Value, want.Value)
+	}
+	return strings.EqualFold(lt.PrimaryLanguage(), b.language) &&
+		slices.EqualFunc(lt.ExtendedLanguageSubtags(), b.extlangs, strings.EqualFold) &&
+		strings.EqualFold(script, b.script) &&
+		strings.EqualFold(region, b.region) &&
+		slices.EqualFunc(lt.VariantSubtags(), b.variants, strings.EqualFold) &&
+		slices.EqualFunc(lt.ExtensionSubtags(), b.extensions, sameExtension) &&
+		slices.EqualFunc(lt.PrivateUseSubtags(), b.privateuse, strings.EqualFold)
+}
+
+// Compose assembles a tag from a primary language, a script, and a region,
+// such as ISO 639, ISO 15924, and ISO 3166 codes, of which the script and the
+// region may be empty. It is a shortcut for a Builder restricted to these
+// subtags, whose result is then validated and canonicalized with
+// ParseAndNormalize: Compose("sr", "latn", "rs") returns "sr-Latn-RS", and a
+// script that is the Suppress-Script of the language is dropped, so
+// Compose("en", "Latn", "US") returns "en-US".
+func (p *Parser) Compose(lang, script, region string) (LanguageTag, error) {
+	lt, err := NewBuilder().Language(lang).Script(script).Region(region).Build(p)
+	if err != nil {
+		return LanguageTag{}, err
+	}
+	return p.ParseAndNormalize(lt.String())
+}
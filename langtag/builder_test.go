@@ -0,0 +1,165 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"errors"
+	"testing"
+)
+
+// mustBuild is a test helper that builds a tag with the global parser and
+// fails the test if an error occurs.
+func mustBuild(t *testing.T, b *Builder) LanguageTag {
+	t.Helper()
+	lt, err := b.Build(p)
+	if err != nil {
+		t.Fatalf("mustBuild failed for %+v: %v", b, err)
+	}
+	return lt
+}
+
+// TestBuilder_Build tests assembling well-formed tags from their subtags.
+func TestBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+		want    string
+	}{
+		{name: "Language only", builder: NewBuilder().Language("EN"), want: "en"},
+		{name: "Language and region", builder: NewBuilder().Language("en").Region("us"), want: "en-US"},
+		{
+			name:    "Language, script and region",
+			builder: NewBuilder().Language("sr").Script("LATN").Region("rs"),
+			want:    "sr-Latn-RS",
+		},
+		{name: "Numeric region", builder: NewBuilder().Language("es").Region("419"), want: "es-419"},
+		{name: "Extlang", builder: NewBuilder().Language("zh").Extlang("yue").Region("HK"), want: "zh-yue-HK"},
+		{
+			name:    "Variants",
+			builder: NewBuilder().Language("sl").AddVariant("rozaj").AddVariant("BISKE"),
+			want:    "sl-rozaj-biske",
+		},
+		{
+			name: "Extensions and private use",
+			builder: NewBuilder().Language("de").Region("DE").
+				AddExtension('U', "CO-phonebk").AddExtension('a', "bbb").PrivateUse("Priv", "two"),
+			want: "de-DE-u-co-phonebk-a-bbb-x-priv-two",
+		},
+		{name: "Private use only", builder: NewBuilder().PrivateUse("whatever"), want: "x-whatever"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mustBuild(t, tt.builder)
+			if got.String() != tt.want {
+				t.Errorf("Build() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("Zero value", func(t *testing.T) {
+		var b Builder
+		got := mustBuild(t, b.Language("fr").Region("CA"))
+		if region, _ := got.Region(); region != "CA" {
+			t.Errorf("Region() = %q, want %q", region, "CA")
+		}
+	})
+}
+
+// TestBuilder_Build_Invalid tests that malformed combinations are rejected.
+func TestBuilder_Build_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+		wantErr error
+	}{
+		{
+			name:    "Second primary language",
+			builder: NewBuilder().Language("en").Language("fr"),
+			wantErr: ErrDuplicateLanguage,
+		},
+		{
+			name:    "Second extlang",
+			builder: NewBuilder().Language("zh").Extlang("yue").Extlang("gan"),
+			wantErr: ErrTooManyExtlangs,
+		},
+		{name: "No language", builder: NewBuilder().Region("US"), wantErr: ErrEmptySubtag},
+		{name: "Empty builder", builder: NewBuilder(), wantErr: ErrEmptySubtag},
+		{name: "Forbidden character", builder: NewBuilder().Language("en").Region("U_"), wantErr: ErrForbiddenChar},
+		{
+			name:    "Subtag too long",
+			builder: NewBuilder().Language("en").AddVariant("toolongvariant"),
+			wantErr: ErrSubtagTooLong,
+		},
+		{name: "Region is a variant", builder: NewBuilder().Language("de").Region("1901"), wantErr: ErrInvalidSubtag},
+		{name: "Script is a region", builder: NewBuilder().Language("en").Script("US"), wantErr: ErrInvalidSubtag},
+		{
+			name:    "Private use as extension",
+			builder: NewBuilder().Language("en").AddExtension('x', "foo"),
+			wantErr: ErrInvalidSubtag,
+		},
+		{
+			name:    "Empty extension",
+			builder: NewBuilder().Language("en").AddExtension('u', ""),
+			wantErr: ErrEmptyExtension,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build(p)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestParser_Compose tests assembling tags from a language, a script, and a
+// region.
+func TestParser_Compose(t *testing.T) {
+	tests := []struct {
+		name                 string
+		lang, script, region string
+		want                 string
+		wantErr              error
+	}{
+		{name: "Language and region", lang: "en", region: "US", want: "en-US"},
+		{name: "All components", lang: "sr", script: "Latn", region: "RS", want: "sr-Latn-RS"},
+		{name: "Canonical casing", lang: "SR", script: "latn", region: "rs", want: "sr-Latn-RS"},
+		{name: "Language only", lang: "fr", want: "fr"},
+		{name: "Numeric region", lang: "es", region: "419", want: "es-419"},
+		{name: "Language and script", lang: "zh", script: "Hant", want: "zh-Hant"},
+		{name: "Suppressed script", lang: "en", script: "Latn", region: "US", want: "en-US"},
+		{name: "Deprecated language", lang: "iw", region: "IL", want: "he-IL"},
+		{name: "No language", region: "US", wantErr: ErrEmptySubtag},
+		{name: "Script in region position", lang: "en", region: "Latn", wantErr: ErrInvalidSubtag},
+		{name: "Unknown region", lang: "en", region: "UK", wantErr: ErrInvalidSubtag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Compose(tt.lang, tt.script, tt.region)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Compose() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Compose() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
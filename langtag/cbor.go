@@ -0,0 +1,45 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"fmt"
+
+	"github.com/jplu/trident/internal/cbor"
+)
+
+// MarshalCBOR implements the cbor.Marshaler interface of CBOR libraries such
+// as github.com/fxamacker/cbor, encoding the language tag as an untagged text
+// string. Tag 38 is not used: it marks a text annotated with a language tag,
+// not a language tag by itself.
+func (lt *LanguageTag) MarshalCBOR() ([]byte, error) {
+	return cbor.AppendText(nil, lt.tag), nil
+}
+
+// UnmarshalCBOR implements the cbor.Unmarshaler interface of CBOR libraries.
+// It accepts an untagged text string, which, like UnmarshalJSON, is fully
+// checked for validity and canonicalized. An empty text yields the zero value.
+func (lt *LanguageTag) UnmarshalCBOR(data []byte) error {
+	s, tags, err := cbor.DecodeText(data)
+	if err != nil {
+		return err
+	}
+	if len(tags) != 0 {
+		return fmt.Errorf("%w: unexpected tag %v for a language tag", cbor.ErrUnexpectedType, tags)
+	}
+	return lt.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jplu/trident/internal/cbor"
+)
+
+// TestLanguageTag_CBOR tests encoding a LanguageTag as a CBOR text string,
+// and decoding it back.
+func TestLanguageTag_CBOR(t *testing.T) {
+	in := mustParseAndNormalize(t, "de-CH-1996")
+	data, err := in.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR() returned an unexpected error: %v", err)
+	}
+	if want := append([]byte{0x6a}, "de-CH-1996"...); !bytes.Equal(data, want) {
+		t.Errorf("MarshalCBOR() = %x, want %x", data, want)
+	}
+
+	for _, tag := range []string{"de-CH-1996", "zh-Hant-TW", "en-US-u-ca-gregory", "x-private", ""} {
+		t.Run(tag, func(t *testing.T) {
+			var orig LanguageTag
+			if tag != "" {
+				orig = mustParseAndNormalize(t, tag)
+			}
+			encoded, marshalErr := orig.MarshalCBOR()
+			if marshalErr != nil {
+				t.Fatalf("MarshalCBOR() returned an unexpected error: %v", marshalErr)
+			}
+			var out LanguageTag
+			if unmarshalErr := out.UnmarshalCBOR(encoded); unmarshalErr != nil {
+				t.Fatalf("UnmarshalCBOR() returned an unexpected error: %v", unmarshalErr)
+			}
+			if !out.Equal(orig) {
+				t.Errorf("round trip produced %q, want %q", out.String(), orig.String())
+			}
+		})
+	}
+}
+
+// TestLanguageTag_UnmarshalCBOR tests that decoding canonicalizes tags and
+// rejects invalid ones, like UnmarshalJSON does, as well as other data items.
+func TestLanguageTag_UnmarshalCBOR(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		wantErr error
+	}{
+		{name: "Canonicalized", data: cbor.AppendText(nil, "EN-latn-us"), want: "en-US"},
+		{name: "Indefinite length", data: []byte{0x7f, 0x62, 'e', 'n', 0x63, '-', 'U', 'S', 0xff}, want: "en-US"},
+		{name: "Invalid tag", data: cbor.AppendText(nil, "en-a-"), wantErr: ErrEmptyExtension},
+		{name: "Tagged", data: cbor.AppendText(nil, "en", 38), wantErr: cbor.ErrUnexpectedType},
+		{name: "Byte string", data: []byte{0x42, 'e', 'n'}, wantErr: cbor.ErrUnexpectedType},
+		{name: "Truncated", data: []byte{0x62, 'e'}, wantErr: cbor.ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out LanguageTag
+			err := out.UnmarshalCBOR(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UnmarshalCBOR() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if out.String() != tt.want {
+				t.Errorf("UnmarshalCBOR() = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import "strings"
+
+// DisplayName returns a human-readable name for a language tag, composed from
+// the registry descriptions of its primary language, script and region
+// subtags. For instance, "sr-Latn-RS" is described as "Serbian (Latin,
+// Serbia)" and "en-US" as "English (United States)". Only the first
+// description of each subtag is used, and a script or region missing from
+// the registry is shown as the subtag itself.
+//
+// The IANA registry only provides English descriptions, so the returned name
+// is not localized. It returns false if the primary language subtag is not in
+// the registry.
+func (p *Parser) DisplayName(lt LanguageTag) (string, bool) {
+	name, ok := p.description("language", lt.PrimaryLanguage())
+	if !ok {
+		return "", false
+	}
+
+	var details []string
+	if script, hasScript := lt.Script(); hasScript {
+		details = append(details, p.descriptionOrSubtag("script", script))
+	}
+	if region, hasRegion := lt.Region(); hasRegion {
+		details = append(details, p.descriptionOrSubtag("region", region))
+	}
+	if len(details) == 0 {
+		return name, true
+	}
+	return name + " (" + strings.Join(details, ", ") + ")", true
+}
+
+// description returns the first registry description of a subtag of the
+// given type.
+func (p *Parser) description(subtagType, subtag string) (string, bool) {
+	rec, ok := p.registry.Records[subtagType+":"+strings.ToLower(subtag)]
+	if !ok || rec.Type != subtagType || len(rec.Description) == 0 {
+		return "", false
+	}
+	return rec.Description[0], true
+}
+
+// descriptionOrSubtag returns the first registry description of a subtag of
+// the given type, or the subtag itself if the registry does not describe it.
+func (p *Parser) descriptionOrSubtag(subtagType, subtag string) string {
+	if desc, ok := p.description(subtagType, subtag); ok {
+		return desc
+	}
+	return subtag
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import "testing"
+
+// TestParser_DisplayName tests the composition of human-readable names from
+// the registry descriptions.
+func TestParser_DisplayName(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   string
+		wantOk bool
+	}{
+		{tag: "sr-Latn-RS", want: "Serbian (Latin, Serbia)", wantOk: true},
+		{tag: "en-US", want: "English (United States)", wantOk: true},
+		{tag: "en", want: "English", wantOk: true},
+		{tag: "zh-Hant", want: "Chinese (Han (Traditional variant))", wantOk: true},
+		{tag: "es-419", want: "Spanish (Latin America and the Caribbean)", wantOk: true},
+		{tag: "de-CH-1901", want: "German (Switzerland)", wantOk: true},
+		{tag: "en-Qaaa-AA", want: "English (Private use, Private use)", wantOk: true},
+		{tag: "en-Zzzy-ZY", want: "English (Zzzy, ZY)", wantOk: true},
+		{tag: "zz-US"},
+		{tag: "x-private"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, ok := p.DisplayName(mustParse(t, tt.tag))
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("DisplayName() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import "strings"
+
+// Distances between the subtags of two language tags, loosely following the
+// default buckets of the CLDR language matching data.
+const (
+	distanceOther         = 1  // Only variants, extensions or private use differ.
+	distanceRegion        = 4  // The regions differ.
+	distanceMacrolanguage = 10 // One language is an encompassed language of the other.
+	distanceScript        = 50 // The scripts differ.
+	distanceLanguage      = 80 // The languages are unrelated.
+)
+
+// Distance returns how far apart two language tags are. It returns 0 when the
+// tags are identical after canonicalization, and otherwise adds a penalty for
+// each differing component: a small one for the region, a larger one for the
+// script, and the largest for the language. A language and its macrolanguage
+// (e.g., "cmn" and "zh") are considered close rather than unrelated. Tags that
+// only differ in their variants, extensions or private use subtags are at a
+// distance of 1.
+//
+// The values are only meant to rank candidates against each other: they
+// roughly follow the CLDR language matching distances, but do not take
+// likely subtags or regional groupings into account.
+func (p *Parser) Distance(a, b LanguageTag) int {
+	ca := p.canonicalOrSelf(a)
+	cb := p.canonicalOrSelf(b)
+	if strings.EqualFold(ca.String(), cb.String()) {
+		return 0
+	}
+
+	distance := p.languageDistance(strings.ToLower(ca.PrimaryLanguage()), strings.ToLower(cb.PrimaryLanguage()))
+	scriptA, _ := ca.Script()
+	scriptB, _ := cb.Script()
+	if !strings.EqualFold(scriptA, scriptB) {
+		distance += distanceScript
+	}
+	regionA, _ := ca.Region()
+	regionB, _ := cb.Region()
+	if !strings.EqualFold(regionA, regionB) {
+		distance += distanceRegion
+	}
+
+	if distance == 0 {
+		return distanceOther
+	}
+	return distance
+}
+
+// BestMatchByDistance returns the tag of have closest to want according to
+// Distance, together with its distance. On ties, the first tag of have wins.
+// If have is empty, it returns the zero LanguageTag and -1.
+func (p *Parser) BestMatchByDistance(want LanguageTag, have []LanguageTag) (LanguageTag, int) {
+	best := -1
+	bestDistance := -1
+	for i := range have {
+		d := p.Distance(want, have[i])
+		if best < 0 || d < bestDistance {
+			best, bestDistance = i, d
+			if d == 0 {
+				break
+			}
+		}
+	}
+	if best < 0 {
+		return LanguageTag{}, -1
+	}
+	return have[best], bestDistance
+}
+
+// languageDistance returns the distance between two lowercase primary
+// language subtags, using the registry to detect macrolanguage relationships.
+func (p *Parser) languageDistance(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a != "" && b != "" && (p.macrolanguage(a) == b || p.macrolanguage(b) == a) {
+		return distanceMacrolanguage
+	}
+	return distanceLanguage
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import "testing"
+
+// TestParser_Distance tests the closeness score between two language tags.
+func TestParser_Distance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "en-US", b: "en-US", want: 0},
+		{a: "en-us", b: "EN-US", want: 0},
+		{a: "iw", b: "he", want: 0},
+		{a: "en-US", b: "en-US-posix", want: distanceOther},
+		{a: "en-US", b: "en-US-x-foo", want: distanceOther},
+		{a: "en-US", b: "en-GB", want: distanceRegion},
+		{a: "en", b: "en-GB", want: distanceRegion},
+		{a: "sr-Latn", b: "sr-Cyrl", want: distanceScript},
+		{a: "sr-Latn-RS", b: "sr-Cyrl-ME", want: distanceScript + distanceRegion},
+		{a: "zh", b: "cmn", want: distanceMacrolanguage},
+		{a: "zh-cmn-Hans", b: "zh-Hans", want: distanceMacrolanguage},
+		{a: "cmn", b: "yue", want: distanceLanguage},
+		{a: "en", b: "fr", want: distanceLanguage},
+		{a: "en-US", b: "fr-FR", want: distanceLanguage + distanceRegion},
+		{a: "x-foo", b: "x-bar", want: distanceOther},
+		{a: "en", b: "x-foo", want: distanceLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			a, b := mustParse(t, tt.a), mustParse(t, tt.b)
+			if got := p.Distance(a, b); got != tt.want {
+				t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := p.Distance(b, a); got != tt.want {
+				t.Errorf("Distance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParser_BestMatchByDistance tests the selection of the closest tag.
+func TestParser_BestMatchByDistance(t *testing.T) {
+	tests := []struct {
+		name         string
+		want         string
+		have         []string
+		wantTag      string
+		wantDistance int
+	}{
+		{
+			name:         "Exact match",
+			want:         "fr-CA",
+			have:         []string{"fr-FR", "fr-CA", "en"},
+			wantTag:      "fr-CA",
+			wantDistance: 0,
+		},
+		{
+			name:         "Region mismatch preferred over script mismatch",
+			want:         "sr-Latn-RS",
+			have:         []string{"sr-Cyrl-RS", "sr-Latn-ME", "hr"},
+			wantTag:      "sr-Latn-ME",
+			wantDistance: distanceRegion,
+		},
+		{
+			name:         "Macrolanguage",
+			want:         "cmn-Hans",
+			have:         []string{"ja", "zh-Hans"},
+			wantTag:      "zh-Hans",
+			wantDistance: distanceMacrolanguage,
+		},
+		{
+			name:         "First tag wins on ties",
+			want:         "en-US",
+			have:         []string{"en-GB", "en-AU"},
+			wantTag:      "en-GB",
+			wantDistance: distanceRegion,
+		},
+		{
+			name:         "Empty candidates",
+			want:         "en",
+			wantDistance: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, d := p.BestMatchByDistance(mustParse(t, tt.want), parseAll(t, tt.have...))
+			if got.String() != tt.wantTag || d != tt.wantDistance {
+				t.Errorf("BestMatchByDistance() = (%q, %d), want (%q, %d)",
+					got.String(), d, tt.wantTag, tt.wantDistance)
+			}
+		})
+	}
+}
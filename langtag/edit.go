@@ -0,0 +1,128 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import "strings"
+
+// WithRegion returns a copy of the tag with its region subtag replaced by
+// region, or added if the tag has none (e.g., "en-US" becomes "en-GB"). An
+// empty region removes the region subtag. Variants, extensions and private use
+// subtags are preserved. The tag itself is not modified.
+//
+// It returns ErrInvalidSubtag if region is neither two letters nor three
+// digits, and ErrInvalidLanguage if the tag has no primary language subtag
+// (e.g., "x-whatever" or "i-klingon").
+func (lt *LanguageTag) WithRegion(region string) (LanguageTag, error) {
+	if region != "" && !isRegionSyntax(region) {
+		return LanguageTag{}, ErrInvalidSubtag
+	}
+	if lt.positions.languageEnd < minLanguageLen {
+		return LanguageTag{}, ErrInvalidLanguage
+	}
+	pos := lt.positions
+	return rebuildTag(lt.tag[:pos.scriptEnd], region, lt.tag[pos.regionEnd:])
+}
+
+// WithScript returns a copy of the tag with its script subtag replaced by
+// script, or added if the tag has none (e.g., "sr-RS" becomes "sr-Latn-RS").
+// An empty script removes the script subtag. Region, variants, extensions and
+// private use subtags are preserved. The tag itself is not modified.
+//
+// It returns ErrInvalidSubtag if script is not made of four letters, and
+// ErrInvalidLanguage if the tag has no primary language subtag.
+func (lt *LanguageTag) WithScript(script string) (LanguageTag, error) {
+	if script != "" && !isScriptSyntax(script) {
+		return LanguageTag{}, ErrInvalidSubtag
+	}
+	if lt.positions.languageEnd < minLanguageLen {
+		return LanguageTag{}, ErrInvalidLanguage
+	}
+	pos := lt.positions
+	return rebuildTag(lt.tag[:pos.extlangEnd], script, lt.tag[pos.scriptEnd:])
+}
+
+// WithoutVariants returns a copy of the tag without its variant subtags (e.g.,
+// "de-CH-1901" becomes "de-CH"). Extensions and private use subtags are
+// preserved. A tag without a primary language subtag is returned unchanged.
+func (lt *LanguageTag) WithoutVariants() LanguageTag {
+	pos := lt.positions
+	if pos.languageEnd < minLanguageLen || pos.variantEnd == pos.regionEnd {
+		return *lt
+	}
+	edited, err := parseWithoutRegistry(lt.tag[:pos.regionEnd] + lt.tag[pos.variantEnd:])
+	if err != nil {
+		return *lt
+	}
+	return edited
+}
+
+// Truncate returns a copy of the tag coarsened to its language subtags and
+// the selected components, e.g., to reduce the precision of a user's locale.
+// For example, "sr-Latn-RS-x-foo" becomes "sr" when nothing is kept, and
+// "sr-RS" when only the region is kept. Unlike Parent, the subtags are removed
+// in a single step. Unless every component is kept, in which case the tag is
+// returned unchanged, extensions and private use subtags are dropped as well,
+// as they qualify the dropped components. A tag without a primary language
+// subtag is returned unchanged.
+func (lt *LanguageTag) Truncate(keepScript, keepRegion, keepVariants bool) LanguageTag {
+	pos := lt.positions
+	if pos.languageEnd < minLanguageLen || (keepScript && keepRegion && keepVariants) {
+		return *lt
+	}
+	var b strings.Builder
+	b.WriteString(lt.tag[:pos.extlangEnd])
+	if keepScript {
+		b.WriteString(lt.tag[pos.extlangEnd:pos.scriptEnd])
+	}
+	if keepRegion {
+		b.WriteString(lt.tag[pos.scriptEnd:pos.regionEnd])
+	}
+	if keepVariants {
+		b.WriteString(lt.tag[pos.regionEnd:pos.variantEnd])
+	}
+	truncated, err := parseWithoutRegistry(b.String())
+	if err != nil {
+		return *lt
+	}
+	return truncated
+}
+
+// isRegionSyntax checks if a subtag has the syntax of a region: two letters or
+// three digits.
+func isRegionSyntax(s string) bool {
+	return (len(s) == regionAlphaLen && isAlphabetic(s)) || (len(s) == regionNumericLen && isNumeric(s))
+}
+
+// isScriptSyntax checks if a subtag has the syntax of a script: four letters.
+func isScriptSyntax(s string) bool {
+	return len(s) == scriptLen && isAlphabetic(s)
+}
+
+// rebuildTag parses the tag made of a prefix, an optional subtag and a suffix,
+// which starts with a '-' unless it is empty. Parsing normalizes the case of
+// the new subtag and computes the positions of the components.
+func rebuildTag(prefix, subtag, suffix string) (LanguageTag, error) {
+	var b strings.Builder
+	b.Grow(len(prefix) + 1 + len(subtag) + len(suffix))
+	b.WriteString(prefix)
+	if subtag != "" {
+		b.WriteByte('-')
+		b.WriteString(subtag)
+	}
+	b.WriteString(suffix)
+	return parseWithoutRegistry(b.String())
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestLanguageTag_WithRegion tests replacing, adding and removing the region.
+func TestLanguageTag_WithRegion(t *testing.T) {
+	tests := []struct {
+		tag     string
+		region  string
+		want    string
+		wantErr error
+	}{
+		{tag: "en-US", region: "GB", want: "en-GB"},
+		{tag: "en", region: "gb", want: "en-GB"},
+		{tag: "es", region: "419", want: "es-419"},
+		{tag: "sr-Latn", region: "RS", want: "sr-Latn-RS"},
+		{tag: "de-CH-1901-u-co-phonebk-x-priv", region: "DE", want: "de-DE-1901-u-co-phonebk-x-priv"},
+		{tag: "zh-yue-HK", region: "MO", want: "zh-yue-MO"},
+		{tag: "en-US-x-twain", region: "", want: "en-x-twain"},
+		{tag: "en-US", region: "USA", wantErr: ErrInvalidSubtag},
+		{tag: "en-US", region: "G1", wantErr: ErrInvalidSubtag},
+		{tag: "en-US", region: "4190", wantErr: ErrInvalidSubtag},
+		{tag: "x-private", region: "US", wantErr: ErrInvalidLanguage},
+		{tag: "i-klingon", region: "US", wantErr: ErrInvalidLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag+" "+tt.region, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			got, err := lt.WithRegion(tt.region)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WithRegion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.String() != tt.want {
+				t.Errorf("WithRegion() = %q, want %q", got.String(), tt.want)
+			}
+			if original := mustParse(t, tt.tag); lt.String() != original.String() {
+				t.Errorf("WithRegion() modified the original tag to %q", lt.String())
+			}
+		})
+	}
+
+	t.Run("Positions are updated", func(t *testing.T) {
+		lt := mustParse(t, "de-CH-1901-u-co-phonebk")
+		got, err := lt.WithRegion("AT")
+		if err != nil {
+			t.Fatalf("WithRegion() unexpected error: %v", err)
+		}
+		if region, _ := got.Region(); region != "AT" {
+			t.Errorf("Region() = %q, want %q", region, "AT")
+		}
+		if variant, _ := got.Variant(); variant != "1901" {
+			t.Errorf("Variant() = %q, want %q", variant, "1901")
+		}
+		if exts := got.ExtensionSubtags(); len(exts) != 1 || exts[0].Value != "co-phonebk" {
+			t.Errorf("ExtensionSubtags() = %v, want [{u co-phonebk}]", exts)
+		}
+	})
+}
+
+// TestLanguageTag_WithScript tests replacing, adding and removing the script.
+func TestLanguageTag_WithScript(t *testing.T) {
+	tests := []struct {
+		tag     string
+		script  string
+		want    string
+		wantErr error
+	}{
+		{tag: "sr-Cyrl-RS", script: "Latn", want: "sr-Latn-RS"},
+		{tag: "sr-RS", script: "latn", want: "sr-Latn-RS"},
+		{tag: "zh", script: "HANT", want: "zh-Hant"},
+		{tag: "zh-Hant-TW-u-nu-hanidec", script: "", want: "zh-TW-u-nu-hanidec"},
+		{tag: "sr", script: "Lat", wantErr: ErrInvalidSubtag},
+		{tag: "sr", script: "La1n", wantErr: ErrInvalidSubtag},
+		{tag: "x-private", script: "Latn", wantErr: ErrInvalidLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag+" "+tt.script, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			got, err := lt.WithScript(tt.script)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WithScript() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.String() != tt.want {
+				t.Errorf("WithScript() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestLanguageTag_WithoutVariants tests the removal of the variants.
+func TestLanguageTag_WithoutVariants(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{tag: "de-CH-1901", want: "de-CH"},
+		{tag: "sl-rozaj-biske-1994", want: "sl"},
+		{tag: "de-DE-1996-u-co-phonebk-x-priv", want: "de-DE-u-co-phonebk-x-priv"},
+		{tag: "en-US", want: "en-US"},
+		{tag: "x-private", want: "x-private"},
+		{tag: "i-klingon", want: "i-klingon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			got := lt.WithoutVariants()
+			if got.String() != tt.want {
+				t.Errorf("WithoutVariants() = %q, want %q", got.String(), tt.want)
+			}
+			if _, ok := got.Variant(); ok && !got.IsGrandfathered() {
+				t.Errorf("WithoutVariants() result %q still has variants", got.String())
+			}
+		})
+	}
+}
+
+// TestLanguageTag_Truncate tests the coarsening of a tag to selected components.
+func TestLanguageTag_Truncate(t *testing.T) {
+	tests := []struct {
+		tag                                  string
+		keepScript, keepRegion, keepVariants bool
+		want                                 string
+	}{
+		{tag: "sr-Latn-RS-x-foo", want: "sr"},
+		{tag: "sr-Latn-RS-x-foo", keepRegion: true, want: "sr-RS"},
+		{tag: "sr-Latn-RS-x-foo", keepScript: true, keepRegion: true, want: "sr-Latn-RS"},
+		{tag: "sr-Latn-RS-x-foo", keepScript: true, keepRegion: true, keepVariants: true, want: "sr-Latn-RS-x-foo"},
+		{tag: "de-CH-1901-u-co-phonebk", keepVariants: true, want: "de-1901"},
+		{tag: "de-CH-1901-u-co-phonebk", keepRegion: true, want: "de-CH"},
+		{tag: "zh-yue-Hant-HK", keepScript: true, want: "zh-yue-Hant"},
+		{tag: "en-u-ca-gregory", keepScript: true, keepRegion: true, want: "en"},
+		{tag: "x-private", want: "x-private"},
+		{tag: "i-klingon", want: "i-klingon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			got := lt.Truncate(tt.keepScript, tt.keepRegion, tt.keepVariants)
+			if got.String() != tt.want {
+				t.Errorf("Truncate(%v, %v, %v) = %q, want %q",
+					tt.keepScript, tt.keepRegion, tt.keepVariants, got.String(), tt.want)
+			}
+			if want := mustParse(t, tt.want); !got.Equal(want) {
+				t.Errorf("Truncate() result %q differs from the parsed tag %q", got.String(), want.String())
+			}
+		})
+	}
+}
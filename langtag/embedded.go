@@ -20,14 +20,38 @@ import (
 	"bytes"
 	_ "embed" // Note the blank import for go:embed
 	"errors"
+	"io"
+	"sync"
 )
 
+//go:generate go test -run ^TestRegistrySnapshot$ -update-snapshot
+
 //go:embed language-subtag-registry
 var embeddedRegistryData []byte
 
+// defaultParser lazily creates the parser shared by the methods that cannot
+// receive one, such as UnmarshalText.
+//
+//nolint:gochecknoglobals // The default parser is created once and shared by the whole package.
+var defaultParser = sync.OnceValues(newDefaultParser)
+
+// newDefaultParser creates the parser returned by Default, which is shared and
+// therefore refuses to be modified.
+func newDefaultParser() (*Parser, error) {
+	p, err := NewParser()
+	if err != nil {
+		return nil, err
+	}
+	p.shared = true
+	return p, nil
+}
+
 // NewParser creates a new parser instance from the embedded IANA registry.
 //
-// IMPORTANT: This function parses the entire IANA registry on every call and is
+// The registry is loaded from a precomputed snapshot of the parsed file, and is
+// only parsed from its text form if that snapshot is stale.
+//
+// IMPORTANT: This function loads the entire IANA registry on every call and is
 // therefore an expensive operation. For performance, it is strongly recommended
 // to call this function only once at application startup and reuse the returned
 // parser instance throughout your application.
@@ -35,14 +59,47 @@ func NewParser() (*Parser, error) {
 	if len(embeddedRegistryData) == 0 {
 		return nil, errors.New("embedded language-subtag-registry file is empty or not found")
 	}
+	if registry, ok := decodeRegistrySnapshot(embeddedRegistrySnapshot, embeddedRegistryData); ok {
+		return &Parser{
+			registry: registry,
+		}, nil
+	}
+
+	return NewParserFromRegistry(bytes.NewReader(embeddedRegistryData))
+}
 
-	reader := bytes.NewReader(embeddedRegistryData)
-	registry, err := ParseRegistry(reader)
+// NewParserFromRegistry creates a new parser instance from an IANA Language
+// Subtag Registry file read from r, instead of the embedded one. This makes it
+// possible to pin a specific snapshot of the registry, or to provide custom
+// test data. The file is parsed with ParseRegistry, and an error is returned
+// if it contains no record.
+//
+// Like NewParser, this is an expensive operation whose result should be
+// reused.
+func NewParserFromRegistry(r io.Reader) (*Parser, error) {
+	registry, err := ParseRegistry(r)
 	if err != nil {
 		return nil, err
 	}
+	if len(registry.Records) == 0 {
+		return nil, errors.New("language-subtag-registry data is empty or contains no records")
+	}
 
 	return &Parser{
 		registry: registry,
 	}, nil
 }
+
+// Default returns a parser for the embedded IANA registry that is created by
+// NewParser on the first call and shared by all later calls. It is the parser
+// used by UnmarshalText, UnmarshalJSON and Scan. If NewParser fails, the same
+// error is returned by every call.
+//
+// Since every unmarshaling in the process may use it concurrently, the
+// returned parser must never be modified: AddRecord, MergeRegistry and
+// RegisterExtension refuse it with ErrSharedParser. Its read-only methods are
+// safe for concurrent use. To customize the registry, create a dedicated
+// parser with NewParser instead.
+func Default() (*Parser, error) {
+	return defaultParser()
+}
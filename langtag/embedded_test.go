@@ -18,6 +18,7 @@ limitations under the License.
 package langtag
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -106,3 +107,105 @@ func TestNewParser_CorruptedRegistry(t *testing.T) {
 		t.Error("expected a descriptive error message for corrupted data, but got an empty error string")
 	}
 }
+
+// TestNewParserFromRegistry verifies that a parser can be built from a custom
+// registry, and that it only knows the subtags of that registry.
+func TestNewParserFromRegistry(t *testing.T) {
+	data := "File-Date: 2024-07-25\n%%\nType: language\nSubtag: en\nDescription: English\nAdded: 2005-10-16\n" +
+		"%%\nType: region\nSubtag: GB\nDescription: United Kingdom\nAdded: 2005-10-16\n"
+
+	parser, err := NewParserFromRegistry(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewParserFromRegistry() returned an unexpected error: %v", err)
+	}
+	if len(parser.registry.Records) != 2 {
+		t.Errorf("expected 2 records, got %d", len(parser.registry.Records))
+	}
+
+	if _, err = parser.ParseAndNormalize("en-GB"); err != nil {
+		t.Errorf("ParseAndNormalize(\"en-GB\") returned an unexpected error: %v", err)
+	}
+	if _, err = parser.ParseAndNormalize("fr"); err == nil {
+		t.Error("ParseAndNormalize(\"fr\") should have failed with a registry not containing 'fr'")
+	}
+}
+
+// TestNewParserFromRegistry_Errors verifies that NewParserFromRegistry rejects
+// empty and corrupted registries.
+func TestNewParserFromRegistry_Errors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		data        string
+		expectedErr string
+	}{
+		{name: "Empty data", data: "", expectedErr: "language-subtag-registry data is empty or contains no records"},
+		{
+			name:        "Header only",
+			data:        "File-Date: 2024-07-25\n%%\n",
+			expectedErr: "language-subtag-registry data is empty or contains no records",
+		},
+		{
+			name:        "Corrupted range",
+			data:        "File-Date: 2024-07-25\n%%\nType: region\nSubtag: 123..abc\nDescription: Corrupted",
+			expectedErr: "failed to expand subtag range",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser, err := NewParserFromRegistry(strings.NewReader(tc.data))
+			if err == nil {
+				t.Fatal("NewParserFromRegistry() should have failed but did not")
+			}
+			if parser != nil {
+				t.Fatalf("NewParserFromRegistry() should have returned a nil parser on failure, but got %v", parser)
+			}
+			if !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("expected error to contain %q, but got: %v", tc.expectedErr, err.Error())
+			}
+		})
+	}
+}
+
+// TestDefault verifies that Default returns a working parser, and that the
+// same instance is shared by every call.
+func TestDefault(t *testing.T) {
+	first, err := Default()
+	if err != nil {
+		t.Fatalf("Default() returned an unexpected error: %v", err)
+	}
+	second, err := Default()
+	if err != nil {
+		t.Fatalf("Default() returned an unexpected error: %v", err)
+	}
+	if first != second {
+		t.Error("Default() returned different parser instances")
+	}
+	if _, err = first.Parse("en-US"); err != nil {
+		t.Errorf("Parse() with the default parser returned an unexpected error: %v", err)
+	}
+
+	t.Run("Refuses to be modified", func(t *testing.T) {
+		records := len(first.registry.Records)
+		if err := first.AddRecord(Record{Type: "language", Subtag: "qtx"}); !errors.Is(err, ErrSharedParser) {
+			t.Errorf("AddRecord() error = %v, want %v", err, ErrSharedParser)
+		}
+		if err := first.MergeRegistry(&Registry{}); !errors.Is(err, ErrSharedParser) {
+			t.Errorf("MergeRegistry() error = %v, want %v", err, ErrSharedParser)
+		}
+		if err := first.RegisterExtension('a'); !errors.Is(err, ErrSharedParser) {
+			t.Errorf("RegisterExtension() error = %v, want %v", err, ErrSharedParser)
+		}
+		if len(first.registry.Records) != records || len(first.extensionSingletons) != 0 {
+			t.Error("Expected the default parser to be left unchanged")
+		}
+		// A parser created by NewParser can still be customized.
+		own, err := NewParser()
+		if err != nil {
+			t.Fatalf("NewParser() returned an unexpected error: %v", err)
+		}
+		if err := own.AddRecord(Record{Type: "language", Subtag: "qtx"}); err != nil {
+			t.Errorf("AddRecord() on a new parser returned an unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Errors that can occur when interpreting the content of an extension.
+var (
+	ErrInvalidUnicodeExtension   = errors.New("the 'u' extension is not a valid Unicode locale extension")
+	ErrInvalidTransformExtension = errors.New("the 't' extension is not a valid transformed content extension")
+	ErrUnregisteredExtension     = errors.New("the extension singleton is not registered")
+)
+
+const (
+	unicodeSingleton   = 'u'    // Singleton of the Unicode locale extension (UTS #35).
+	unicodeKeyLen      = 2      // A Unicode locale key is always 2 characters.
+	unicodeTrue        = "true" // Value of a Unicode locale key given without a type.
+	transformSingleton = 't'    // Singleton of the transformed content extension (UTS #35).
+	transformKeyLen    = 2      // A transform field key is always a letter followed by a digit.
+	minExtensionValue  = 3      // Minimum length of a type, attribute or field value subtag.
+)
+
+// deprecatedUnicodeTypes maps deprecated types of Unicode locale keywords,
+// keyed by "key:type", to their preferred replacement, as listed by the
+// deprecated aliases of the CLDR BCP 47 data.
+//
+//nolint:gochecknoglobals // This is a read-only lookup table.
+var deprecatedUnicodeTypes = map[string]string{
+	"ca:ethiopic-amete-alem": "ethioaa",
+	"ca:islamicc":            "islamic-civil",
+	"kb:yes":                 "true",
+	"kc:yes":                 "true",
+	"kh:yes":                 "true",
+	"kk:yes":                 "true",
+	"kn:yes":                 "true",
+	"ks:identical":           "identic",
+	"ks:primary":             "level1",
+	"ks:quarternary":         "level4",
+	"ks:quaternary":          "level4",
+	"ks:secondary":           "level2",
+	"ks:tertiary":            "level3",
+	"ms:imperial":            "uksystem",
+}
+
+// registeredExtensions lists the singletons of the IANA Language Tag
+// Extensions Registry, which RFC 5646, Section 3.7 sets up separately from the
+// Language Subtag Registry.
+//
+//nolint:gochecknoglobals // This is a read-only lookup table.
+var registeredExtensions = []rune{transformSingleton, unicodeSingleton}
+
+// unicodeKeyword is a key and its type in a Unicode locale extension.
+type unicodeKeyword struct {
+	key, value string
+}
+
+// UnicodeExtensions interprets the 'u' extension of the tag as defined in
+// UTS #35, Section 3.2 (e.g., "en-US-u-ca-gregory-nu-latn"). It returns the
+// keywords as a map from each two-letter key to its type, and the attributes
+// that come before the first keyword. A key given without a type is mapped to
+// "true", its implicit value. When a key is repeated, its first occurrence
+// wins. Keys, types and attributes are returned in lowercase. The canonical
+// order of the keywords is the alphabetical order of their keys, which can be
+// obtained with slices.Sorted(maps.Keys(keywords)).
+//
+// If the tag has no 'u' extension, both results are nil. An error wrapping
+// ErrInvalidUnicodeExtension is returned if the extension does not follow the
+// UTS #35 syntax.
+func (lt *LanguageTag) UnicodeExtensions() (map[string]string, []string, error) {
+	value, ok := lt.extensionValue(unicodeSingleton)
+	if !ok {
+		return nil, nil, nil
+	}
+	attributes, keywords, err := parseUnicodeExtension(value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keywordMap map[string]string
+	if len(keywords) > 0 {
+		keywordMap = make(map[string]string, len(keywords))
+		for _, kw := range keywords {
+			keywordMap[kw.key] = kw.value
+		}
+	}
+	return keywordMap, attributes, nil
+}
+
+// extensionValue returns the value of the first extension of the tag
+// introduced by the given lowercase singleton.
+func (lt *LanguageTag) extensionValue(singleton rune) (string, bool) {
+	for _, ext := range lt.extensions {
+		if ext.Singleton == singleton {
+			return ext.Value, true
+		}
+	}
+	return "", false
+}
+
+// parseUnicodeExtension splits the value of a 'u' extension into its
+// attributes and keywords, in order of appearance and in lowercase. Repeated
+// keys are dropped after their first occurrence.
+func parseUnicodeExtension(value string) ([]string, []unicodeKeyword, error) {
+	var attributes []string
+	var keywords []unicodeKeyword
+	var current *unicodeKeyword
+	seen := make(map[string]struct{})
+	inKeywords := false
+	for subtag := range strings.SplitSeq(strings.ToLower(value), "-") {
+		if len(subtag) == unicodeKeyLen {
+			if !isAlphanum(subtag[0]) || !isAlpha(subtag[1]) {
+				return nil, nil, fmt.Errorf("%w: invalid key '%s'", ErrInvalidUnicodeExtension, subtag)
+			}
+			inKeywords = true
+			current = nil
+			if _, ok := seen[subtag]; !ok {
+				seen[subtag] = struct{}{}
+				keywords = append(keywords, unicodeKeyword{key: subtag})
+				current = &keywords[len(keywords)-1]
+			}
+			continue
+		}
+		if len(subtag) < minExtensionValue || len(subtag) > maxSubtagLen || !isAlphanumeric(subtag) {
+			return nil, nil, fmt.Errorf("%w: invalid subtag '%s'", ErrInvalidUnicodeExtension, subtag)
+		}
+		switch {
+		case !inKeywords:
+			attributes = append(attributes, subtag)
+		case current == nil:
+			// The type of a repeated key is ignored.
+		case current.value == "":
+			current.value = subtag
+		default:
+			current.value += "-" + subtag
+		}
+	}
+
+	for i := range keywords {
+		if keywords[i].value == "" {
+			keywords[i].value = unicodeTrue
+		}
+	}
+	return attributes, keywords, nil
+}
+
+// canonicalUnicodeExtension returns the canonical form of the value of a 'u'
+// extension, as per UTS #35, Section 3.2.1: attributes are sorted and
+// deduplicated, keywords are sorted by key, deprecated types are replaced and
+// the "true" type is omitted. It returns false if the value is not a valid
+// Unicode locale extension.
+func canonicalUnicodeExtension(value string) (string, bool) {
+	attributes, keywords, err := parseUnicodeExtension(value)
+	if err != nil {
+		return "", false
+	}
+	slices.Sort(attributes)
+	attributes = slices.Compact(attributes)
+	slices.SortFunc(keywords, func(a, b unicodeKeyword) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	var b strings.Builder
+	b.Grow(len(value))
+	for _, attribute := range attributes {
+		if b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(attribute)
+	}
+	for _, kw := range keywords {
+		if b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(kw.key)
+		typ := kw.value
+		if preferred, ok := deprecatedUnicodeTypes[kw.key+":"+typ]; ok {
+			typ = preferred
+		}
+		if typ != unicodeTrue {
+			b.WriteByte('-')
+			b.WriteString(typ)
+		}
+	}
+	return b.String(), true
+}
+
+// TransformExtension interprets the 't' extension of the tag as defined in
+// UTS #35, Section 3.2.2 (e.g., "und-Cyrl-t-und-latn-m0-ungegn-2007"). It
+// returns the source language tag the content was transformed from, if any,
+// and the transform fields as a map from each key (a letter followed by a
+// digit, such as "m0") to its value. The source tag is parsed with the same
+// rules as Parse, and both keys and values are returned in lowercase.
+//
+// If the tag has no 't' extension, it returns the zero LanguageTag and a nil
+// map. An error wrapping ErrInvalidTransformExtension is returned if the
+// extension has neither a source tag nor fields, or does not follow the
+// UTS #35 syntax.
+func (lt *LanguageTag) TransformExtension() (LanguageTag, map[string]string, error) {
+	value, ok := lt.extensionValue(transformSingleton)
+	if !ok {
+		return LanguageTag{}, nil, nil
+	}
+	if value == "" {
+		return LanguageTag{}, nil, fmt.Errorf("%w: neither a source tag nor fields", ErrInvalidTransformExtension)
+	}
+
+	subtags := strings.Split(strings.ToLower(value), "-")
+	sourceLen := 0
+	for sourceLen < len(subtags) && !isTransformKey(subtags[sourceLen]) {
+		sourceLen++
+	}
+
+	var source LanguageTag
+	if sourceLen > 0 {
+		var err error
+		source, err = parseWithoutRegistry(strings.Join(subtags[:sourceLen], "-"))
+		if err != nil {
+			return LanguageTag{}, nil, fmt.Errorf("%w: invalid source tag: %w", ErrInvalidTransformExtension, err)
+		}
+	}
+
+	if sourceLen == len(subtags) {
+		return source, nil, nil
+	}
+	fields, err := parseTransformFields(subtags[sourceLen:])
+	if err != nil {
+		return LanguageTag{}, nil, err
+	}
+	return source, fields, nil
+}
+
+// isTransformKey checks if a lowercase subtag is the key of a transform field.
+func isTransformKey(subtag string) bool {
+	return len(subtag) == transformKeyLen && isAlpha(subtag[0]) && isDigit(subtag[1])
+}
+
+// parseTransformFields parses a sequence of lowercase subtags made of transform
+// fields, each of them being a key followed by one or more value subtags. The
+// first subtag must be a key.
+func parseTransformFields(subtags []string) (map[string]string, error) {
+	fields := make(map[string]string)
+	key := ""
+	for _, subtag := range subtags {
+		if isTransformKey(subtag) {
+			if key != "" && fields[key] == "" {
+				return nil, fmt.Errorf("%w: field '%s' has no value", ErrInvalidTransformExtension, key)
+			}
+			if _, ok := fields[subtag]; ok {
+				return nil, fmt.Errorf("%w: duplicate field '%s'", ErrInvalidTransformExtension, subtag)
+			}
+			key = subtag
+			fields[key] = ""
+			continue
+		}
+		if len(subtag) < minExtensionValue || len(subtag) > maxSubtagLen || !isAlphanumeric(subtag) {
+			return nil, fmt.Errorf("%w: invalid subtag '%s'", ErrInvalidTransformExtension, subtag)
+		}
+		if fields[key] == "" {
+			fields[key] = subtag
+		} else {
+			fields[key] += "-" + subtag
+		}
+	}
+	if fields[key] == "" {
+		return nil, fmt.Errorf("%w: field '%s' has no value", ErrInvalidTransformExtension, key)
+	}
+	return fields, nil
+}
+
+// ValidateExtensions checks that every extension of the tag is introduced by a
+// registered singleton: one of the IANA Language Tag Extensions Registry ('t'
+// and 'u') or one added with RegisterExtension. It returns an error wrapping
+// ErrUnregisteredExtension for the first extension that is not. This check is
+// not part of Parse nor ParseAndNormalize, which only require the singletons
+// to be well-formed, as RFC 5646 does.
+func (p *Parser) ValidateExtensions(lt LanguageTag) error {
+	for _, ext := range lt.extensions {
+		if !slices.Contains(registeredExtensions, ext.Singleton) &&
+			!slices.Contains(p.extensionSingletons, ext.Singleton) {
+			return fmt.Errorf("%w: '%c'", ErrUnregisteredExtension, ext.Singleton)
+		}
+	}
+	return nil
+}
+
+// RegisterExtension makes ValidateExtensions accept the extensions introduced
+// by the given singleton, matched case-insensitively, for instance when a new
+// extension is registered with IANA or for a private agreement.
+//
+// Like AddRecord, RegisterExtension must not be called while the parser is
+// being used by other goroutines, and it returns ErrSharedParser for the
+// parser returned by Default.
+func (p *Parser) RegisterExtension(singleton rune) error {
+	if p.shared {
+		return ErrSharedParser
+	}
+	singleton = unicode.ToLower(singleton)
+	if !slices.Contains(p.extensionSingletons, singleton) {
+		p.extensionSingletons = append(p.extensionSingletons, singleton)
+	}
+	return nil
+}
+
+// parseWithoutRegistry parses a well-formed language tag without consulting any
+// registry, so the result is never flagged as grandfathered. It is used where
+// no Parser is at hand, for tags derived from the content of another tag.
+func parseWithoutRegistry(tag string) (LanguageTag, error) {
+	p := &Parser{registry: &Registry{}}
+	return p.Parse(tag)
+}
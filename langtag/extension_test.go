@@ -0,0 +1,193 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestLanguageTag_UnicodeExtensions tests the interpretation of the 'u'
+// extension, using examples from UTS #35.
+func TestLanguageTag_UnicodeExtensions(t *testing.T) {
+	tests := []struct {
+		tag            string
+		wantKeywords   map[string]string
+		wantAttributes []string
+		wantErr        error
+	}{
+		{tag: "de-DE-u-co-phonebk", wantKeywords: map[string]string{"co": "phonebk"}},
+		{tag: "en-US-u-ca-gregory-nu-latn", wantKeywords: map[string]string{"ca": "gregory", "nu": "latn"}},
+		{tag: "th-TH-u-nu-thai", wantKeywords: map[string]string{"nu": "thai"}},
+		{tag: "ar-u-ca-islamic-civil", wantKeywords: map[string]string{"ca": "islamic-civil"}},
+		{tag: "en-u-kn", wantKeywords: map[string]string{"kn": "true"}},
+		{tag: "en-u-kn-true", wantKeywords: map[string]string{"kn": "true"}},
+		{tag: "en-u-CA-Buddhist", wantKeywords: map[string]string{"ca": "buddhist"}},
+		{tag: "en-u-ca-buddhist-ca-gregory", wantKeywords: map[string]string{"ca": "buddhist"}},
+		{
+			tag:            "en-u-foo-bar-nu-thai",
+			wantKeywords:   map[string]string{"nu": "thai"},
+			wantAttributes: []string{"foo", "bar"},
+		},
+		{tag: "en-u-foo", wantAttributes: []string{"foo"}},
+		{tag: "en-a-bbb-u-nu-arab-x-ca-foo", wantKeywords: map[string]string{"nu": "arab"}},
+		{tag: "en-US"},
+		{tag: "en-a-bbb"},
+		{tag: "en-u-a1-foo", wantErr: ErrInvalidUnicodeExtension},
+		{tag: "en-u-ca-gregory-1a-foo-a1", wantErr: ErrInvalidUnicodeExtension},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			keywords, attributes, err := lt.UnicodeExtensions()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UnicodeExtensions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(keywords, tt.wantKeywords) {
+				t.Errorf("UnicodeExtensions() keywords = %v, want %v", keywords, tt.wantKeywords)
+			}
+			if !reflect.DeepEqual(attributes, tt.wantAttributes) {
+				t.Errorf("UnicodeExtensions() attributes = %v, want %v", attributes, tt.wantAttributes)
+			}
+		})
+	}
+}
+
+// TestParseUnicodeExtension tests that keywords and attributes keep their
+// order of appearance.
+func TestParseUnicodeExtension(t *testing.T) {
+	attributes, keywords, err := parseUnicodeExtension("zzz-aaa-nu-thai-ca-islamic-civil-kn")
+	if err != nil {
+		t.Fatalf("parseUnicodeExtension() unexpected error: %v", err)
+	}
+	wantAttributes := []string{"zzz", "aaa"}
+	wantKeywords := []unicodeKeyword{
+		{key: "nu", value: "thai"},
+		{key: "ca", value: "islamic-civil"},
+		{key: "kn", value: "true"},
+	}
+	if !reflect.DeepEqual(attributes, wantAttributes) {
+		t.Errorf("parseUnicodeExtension() attributes = %v, want %v", attributes, wantAttributes)
+	}
+	if !reflect.DeepEqual(keywords, wantKeywords) {
+		t.Errorf("parseUnicodeExtension() keywords = %v, want %v", keywords, wantKeywords)
+	}
+}
+
+// TestLanguageTag_TransformExtension tests the interpretation of the 't'
+// extension, using examples from UTS #35.
+func TestLanguageTag_TransformExtension(t *testing.T) {
+	tests := []struct {
+		tag        string
+		wantSource string
+		wantFields map[string]string
+		wantErr    error
+	}{
+		{tag: "ja-t-it", wantSource: "it"},
+		{tag: "und-Latn-t-und-hani-m0-names", wantSource: "und-Hani", wantFields: map[string]string{"m0": "names"}},
+		{
+			tag:        "und-Cyrl-t-und-latn-m0-ungegn-2007",
+			wantSource: "und-Latn",
+			wantFields: map[string]string{"m0": "ungegn-2007"},
+		},
+		{
+			tag:        "und-Latn-t-ja-t0-und-m0-alaloc",
+			wantSource: "ja",
+			wantFields: map[string]string{"t0": "und", "m0": "alaloc"},
+		},
+		{tag: "en-t-k0-dvorak", wantFields: map[string]string{"k0": "dvorak"}},
+		{tag: "de-t-en-US-h0-hybrid-u-ca-gregory", wantSource: "en-US", wantFields: map[string]string{"h0": "hybrid"}},
+		{tag: "en-US"},
+		{tag: "en-t-m0", wantErr: ErrInvalidTransformExtension},
+		{tag: "en-t-m0-k0-dvorak", wantErr: ErrInvalidTransformExtension},
+		{tag: "en-t-k0-ab", wantErr: ErrInvalidTransformExtension},
+		{tag: "en-t-k0-dvorak-k0-qwerty", wantErr: ErrInvalidTransformExtension},
+		{tag: "en-t-123", wantErr: ErrInvalidTransformExtension},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			source, fields, err := lt.TransformExtension()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TransformExtension() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if source.String() != tt.wantSource {
+				t.Errorf("TransformExtension() source = %q, want %q", source.String(), tt.wantSource)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("TransformExtension() fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+
+	t.Run("Empty extension", func(t *testing.T) {
+		lt := LanguageTag{tag: "en", extensions: []Extension{{Singleton: 't'}}}
+		_, _, err := lt.TransformExtension()
+		if !errors.Is(err, ErrInvalidTransformExtension) {
+			t.Errorf("TransformExtension() error = %v, want %v", err, ErrInvalidTransformExtension)
+		}
+	})
+}
+
+// TestParser_ValidateExtensions tests the check of the extension singletons
+// against the registered ones.
+func TestParser_ValidateExtensions(t *testing.T) {
+	p := newTestParser(nil)
+	tests := []struct {
+		tag     string
+		wantErr error
+	}{
+		{tag: "en-u-ca-gregory"},
+		{tag: "ja-t-it"},
+		{tag: "de-t-en-u-ca-gregory"},
+		{tag: "en-US"},
+		{tag: "en-x-foo"},
+		{tag: "en-a-foo", wantErr: ErrUnregisteredExtension},
+		{tag: "en-u-ca-gregory-b-bar", wantErr: ErrUnregisteredExtension},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			if err := p.ValidateExtensions(lt); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateExtensions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("Registered extension", func(t *testing.T) {
+		custom := newTestParser(nil)
+		if err := custom.RegisterExtension('A'); err != nil {
+			t.Fatalf("RegisterExtension() error = %v, want nil", err)
+		}
+		if err := custom.RegisterExtension('a'); err != nil {
+			t.Fatalf("RegisterExtension() error = %v, want nil", err)
+		}
+		if err := custom.ValidateExtensions(mustParse(t, "en-a-foo")); err != nil {
+			t.Errorf("ValidateExtensions() error = %v, want nil", err)
+		}
+		if len(custom.extensionSingletons) != 1 {
+			t.Errorf("RegisterExtension() registered %q, want a single singleton", custom.extensionSingletons)
+		}
+		if err := p.ValidateExtensions(mustParse(t, "en-a-foo")); !errors.Is(err, ErrUnregisteredExtension) {
+			t.Errorf("ValidateExtensions() on another parser error = %v, want %v", err, ErrUnregisteredExtension)
+		}
+	})
+}
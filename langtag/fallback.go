@@ -0,0 +1,90 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import "strings"
+
+// rootLanguage is the primary language subtag of the root locale, at the end
+// of every fallback chain.
+const rootLanguage = "und"
+
+// minLanguageLen is the minimum length of a primary language subtag.
+const minLanguageLen = 2
+
+// Parent returns the tag that a localization lookup should fall back to when
+// no resource is available for this tag, following the CLDR truncation
+// fallback. Extensions and private use subtags are dropped first, then the
+// most specific trailing subtag is removed at each step: variants from last
+// to first, then the region, the script and the extended language subtags.
+// The parent of a primary language alone is "und", the root locale.
+//
+// For example, the chain of parents of "zh-Hant-TW" is "zh-Hant", "zh" and
+// "und". It returns false if the tag is "und" or empty, since such a tag has
+// no parent. Private use tags, such as "x-whatever", and irregular
+// grandfathered tags, such as "i-klingon", directly fall back to "und".
+func (lt *LanguageTag) Parent() (LanguageTag, bool) {
+	if lt.tag == "" || strings.EqualFold(lt.tag, rootLanguage) {
+		return LanguageTag{}, false
+	}
+
+	pos := lt.positions
+	parent := ""
+	switch {
+	case pos.languageEnd < minLanguageLen:
+		// Private use tags and irregular grandfathered tags such as
+		// "i-klingon" have no primary language to fall back to.
+	case len(lt.tag) > pos.variantEnd:
+		parent = lt.tag[:pos.variantEnd]
+	case pos.variantEnd > pos.regionEnd:
+		parent = lt.tag[:strings.LastIndexByte(lt.tag[:pos.variantEnd], '-')]
+	case pos.regionEnd > pos.scriptEnd:
+		parent = lt.tag[:pos.scriptEnd]
+	case pos.scriptEnd > pos.extlangEnd:
+		parent = lt.tag[:pos.extlangEnd]
+	case pos.extlangEnd > pos.languageEnd:
+		parent = lt.tag[:pos.languageEnd]
+	}
+	if parent == "" {
+		parent = rootLanguage
+	}
+
+	// A prefix of a well-formed tag ending on a subtag boundary is itself
+	// well-formed, so parsing it cannot fail.
+	parentTag, err := parseWithoutRegistry(parent)
+	if err != nil {
+		return LanguageTag{}, false
+	}
+	return parentTag, true
+}
+
+// ParentChain returns the fallback chain of the tag, starting with the tag
+// itself and followed by each of its successive parents, down to "und". It
+// returns nil for an empty tag.
+func (lt *LanguageTag) ParentChain() []LanguageTag {
+	if lt.tag == "" {
+		return nil
+	}
+	chain := []LanguageTag{*lt}
+	for current := *lt; ; {
+		parent, ok := current.Parent()
+		if !ok {
+			return chain
+		}
+		chain = append(chain, parent)
+		current = parent
+	}
+}
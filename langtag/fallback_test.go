@@ -0,0 +1,108 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import "testing"
+
+// TestLanguageTag_Parent tests the truncation fallback of a tag.
+func TestLanguageTag_Parent(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   string
+		wantOk bool
+	}{
+		{tag: "zh-Hant-TW", want: "zh-Hant", wantOk: true},
+		{tag: "zh-Hant", want: "zh", wantOk: true},
+		{tag: "zh", want: "und", wantOk: true},
+		{tag: "und", wantOk: false},
+		{tag: "de-CH-1901-1996", want: "de-CH-1901", wantOk: true},
+		{tag: "de-CH-1901", want: "de-CH", wantOk: true},
+		{tag: "sl-rozaj-biske", want: "sl-rozaj", wantOk: true},
+		{tag: "en-US-u-ca-gregory", want: "en-US", wantOk: true},
+		{tag: "en-US-x-twain", want: "en-US", wantOk: true},
+		{tag: "zh-yue-HK", want: "zh-yue", wantOk: true},
+		{tag: "zh-yue", want: "zh", wantOk: true},
+		{tag: "und-Latn", want: "und", wantOk: true},
+		{tag: "x-private", want: "und", wantOk: true},
+		{tag: "i-klingon", want: "und", wantOk: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			got, ok := lt.Parent()
+			if ok != tt.wantOk {
+				t.Fatalf("Parent() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parent() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("Components of the parent", func(t *testing.T) {
+		lt := mustParse(t, "sr-Latn-RS-u-nu-latn")
+		parent, _ := lt.Parent()
+		if region, ok := parent.Region(); !ok || region != "RS" {
+			t.Errorf("Parent().Region() = (%q, %v), want (\"RS\", true)", region, ok)
+		}
+		if len(parent.ExtensionSubtags()) != 0 {
+			t.Errorf("Parent().ExtensionSubtags() = %v, want none", parent.ExtensionSubtags())
+		}
+	})
+
+	t.Run("Empty tag", func(t *testing.T) {
+		var lt LanguageTag
+		if _, ok := lt.Parent(); ok {
+			t.Error("Parent() of an empty tag reported a parent")
+		}
+	})
+}
+
+// TestLanguageTag_ParentChain tests the full fallback chain of a tag.
+func TestLanguageTag_ParentChain(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{tag: "zh-Hant-TW", want: []string{"zh-Hant-TW", "zh-Hant", "zh", "und"}},
+		{tag: "de-DE-1996-u-co-phonebk", want: []string{"de-DE-1996-u-co-phonebk", "de-DE-1996", "de-DE", "de", "und"}},
+		{tag: "und", want: []string{"und"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			chain := lt.ParentChain()
+			if len(chain) != len(tt.want) {
+				t.Fatalf("ParentChain() has %d tags, want %d", len(chain), len(tt.want))
+			}
+			for i := range chain {
+				if chain[i].String() != tt.want[i] {
+					t.Errorf("ParentChain()[%d] = %q, want %q", i, chain[i].String(), tt.want[i])
+				}
+			}
+		})
+	}
+
+	t.Run("Empty tag", func(t *testing.T) {
+		var lt LanguageTag
+		if chain := lt.ParentChain(); chain != nil {
+			t.Errorf("ParentChain() = %v, want nil", chain)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+// GobEncode implements the gob.GobEncoder interface. Only the tag string is
+// encoded, the positions of its subtags are rebuilt by GobDecode.
+func (lt *LanguageTag) GobEncode() ([]byte, error) {
+	return lt.MarshalText()
+}
+
+// GobDecode implements the gob.GobDecoder interface. Like UnmarshalText, it
+// performs a full validity check and canonicalizes the tag with the parser
+// returned by Default, so a tag that is only valid for a custom registry
+// cannot be decoded. Empty data yields the zero value.
+func (lt *LanguageTag) GobDecode(data []byte) error {
+	return lt.UnmarshalText(data)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+)
+
+// Compile-time checks that LanguageTag implements the encoding/gob interfaces.
+var (
+	_ gob.GobEncoder = (*LanguageTag)(nil)
+	_ gob.GobDecoder = (*LanguageTag)(nil)
+)
+
+// gobDocument holds language tags as a gob-encoded value can.
+type gobDocument struct {
+	Lang      LanguageTag
+	Fallbacks []LanguageTag
+}
+
+// TestLanguageTag_Gob tests encoding LanguageTag values with encoding/gob and
+// decoding them back, with working accessors.
+func TestLanguageTag_Gob(t *testing.T) {
+	in := gobDocument{
+		Lang:      mustParseAndNormalize(t, "sr-Latn-RS-u-ca-gregory"),
+		Fallbacks: []LanguageTag{mustParseAndNormalize(t, "de-CH-1996"), {}},
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&in); err != nil {
+		t.Fatalf("Encode() returned an unexpected error: %v", err)
+	}
+
+	var out gobDocument
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode() returned an unexpected error: %v", err)
+	}
+	if !out.Lang.Equal(in.Lang) || len(out.Fallbacks) != len(in.Fallbacks) {
+		t.Fatalf("round trip produced %+v, want %+v", out, in)
+	}
+	for i := range in.Fallbacks {
+		if out.Fallbacks[i].String() != in.Fallbacks[i].String() {
+			t.Errorf("Fallbacks[%d] = %q, want %q", i, out.Fallbacks[i].String(), in.Fallbacks[i].String())
+		}
+	}
+
+	if got := out.Lang.PrimaryLanguage(); got != "sr" {
+		t.Errorf("PrimaryLanguage() after decoding = %q, want %q", got, "sr")
+	}
+	if script, _ := out.Lang.Script(); script != "Latn" {
+		t.Errorf("Script() after decoding = %q, want %q", script, "Latn")
+	}
+	if region, _ := out.Lang.Region(); region != "RS" {
+		t.Errorf("Region() after decoding = %q, want %q", region, "RS")
+	}
+	if value, _ := out.Lang.Extension('u'); value != "ca-gregory" {
+		t.Errorf("Extension('u') after decoding = %q, want %q", value, "ca-gregory")
+	}
+	if variants := out.Fallbacks[0].VariantSubtags(); len(variants) != 1 || variants[0] != "1996" {
+		t.Errorf("VariantSubtags() after decoding = %v, want [1996]", variants)
+	}
+}
+
+// TestLanguageTag_GobDecode tests that decoding canonicalizes tags and rejects
+// invalid ones.
+func TestLanguageTag_GobDecode(t *testing.T) {
+	var lt LanguageTag
+	if err := lt.GobDecode([]byte("EN-latn-us")); err != nil || lt.String() != "en-US" {
+		t.Errorf("GobDecode() = (%q, %v), want (%q, nil)", lt.String(), err, "en-US")
+	}
+	if err := lt.GobDecode([]byte("en-a-")); !errors.Is(err, ErrEmptyExtension) {
+		t.Errorf("GobDecode() error = %v, want %v", err, ErrEmptyExtension)
+	}
+}
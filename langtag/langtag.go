@@ -42,21 +42,26 @@ package langtag
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"slices"
 	"strings"
+	"unicode"
 )
 
 // Errors that can occur during language tag parsing.
 var (
-	ErrEmptyExtension     = errors.New("if an extension subtag is present, it must not be empty")
-	ErrEmptyPrivateUse    = errors.New("if the 'x' subtag is present, it must not be empty")
-	ErrForbiddenChar      = errors.New("the langtag contains a char not allowed")
-	ErrInvalidSubtag      = errors.New("a subtag fails to parse or is not a valid IANA subtag")
-	ErrInvalidLanguage    = errors.New("the given language subtag is invalid")
-	ErrSubtagTooLong      = errors.New("a subtag may be eight characters in length at maximum")
-	ErrEmptySubtag        = errors.New("a subtag should not be empty")
-	ErrTooManyExtlangs    = errors.New("at maximum one extlang is allowed")
-	ErrDuplicateVariant   = errors.New("the same variant subtag appears more than once")
-	ErrDuplicateSingleton = errors.New("the same extension singleton appears more than once")
+	ErrEmptyExtension        = errors.New("if an extension subtag is present, it must not be empty")
+	ErrEmptyPrivateUse       = errors.New("if the 'x' subtag is present, it must not be empty")
+	ErrForbiddenChar         = errors.New("the langtag contains a char not allowed")
+	ErrInvalidSubtag         = errors.New("a subtag fails to parse or is not a valid IANA subtag")
+	ErrInvalidLanguage       = errors.New("the given language subtag is invalid")
+	ErrSubtagTooLong         = errors.New("a subtag may be eight characters in length at maximum")
+	ErrEmptySubtag           = errors.New("a subtag should not be empty")
+	ErrTooManyExtlangs       = errors.New("at maximum one extlang is allowed")
+	ErrDuplicateVariant      = errors.New("the same variant subtag appears more than once")
+	ErrDuplicateSingleton    = errors.New("the same extension singleton appears more than once")
+	ErrVariantPrefixMismatch = errors.New("a variant subtag is used without any of its registered prefixes")
 )
 
 const typeExtlang = "extlang"
@@ -65,6 +70,12 @@ const typeExtlang = "extlang"
 // and should be created once and reused for efficiency.
 type Parser struct {
 	registry *Registry
+	// extensionSingletons holds the singletons added with RegisterExtension
+	// to those of the IANA Language Tag Extensions Registry.
+	extensionSingletons []rune
+	// shared is true for the parser returned by Default, which must not be
+	// modified.
+	shared bool
 }
 
 // LanguageTag represents a well-formed RFC 5646 language tag.
@@ -72,6 +83,9 @@ type LanguageTag struct {
 	tag        string
 	positions  tagElementsPositions
 	extensions []Extension
+	// validated is true if the tag was checked against the registry when it
+	// was created.
+	validated bool
 }
 
 // Parse checks if a tag is "well-formed" according to RFC 5646 syntax.
@@ -117,10 +131,117 @@ func (p *Parser) Parse(tag string) (LanguageTag, error) {
 	return LanguageTag{tag: renderedTag, positions: positions, extensions: cpr.extensions}, nil
 }
 
+// ParseLenient is like Parse, but also accepts the underscore as a subtag
+// separator, as found in POSIX locales and Java Locale strings, so that
+// "en_US" gives "en-US". The returned tag always uses hyphens.
+//
+// Only the separator is tolerated: every other character forbidden by
+// RFC 5646 is still rejected with ErrForbiddenChar. In particular, the
+// ".charset" and "@modifier" suffixes of POSIX locales, as in "en_US.UTF-8",
+// are not stripped, and such locales are rejected. Use FromPOSIX to convert
+// POSIX locales.
+func (p *Parser) ParseLenient(tag string) (LanguageTag, error) {
+	return p.Parse(strings.ReplaceAll(tag, "_", "-"))
+}
+
+// ParseTo is like Parse, but it stores the result in dst instead of returning
+// a new LanguageTag, so that a single destination can be reused across calls in
+// tight loops. The extension storage of dst is reused rather than reallocated,
+// and when the tag is already in canonical case, its string is kept as is
+// instead of being rebuilt. Any LanguageTag copied from dst before the call
+// must therefore not be used afterwards. On error, dst is reset to an empty
+// LanguageTag, which keeps its extension storage for the next call.
+func (p *Parser) ParseTo(tag string, dst *LanguageTag) error {
+	*dst = LanguageTag{extensions: dst.extensions[:0]}
+	for _, r := range tag {
+		if !isLangtagChar(r) {
+			return ErrForbiddenChar
+		}
+	}
+
+	cpr := p.newCanonicalParseRun(tag, false)
+	cpr.extensions = dst.extensions
+	if err := cpr.parse(); err != nil {
+		// The storage may have grown before the error was found.
+		dst.extensions = cpr.extensions[:0]
+		return err
+	}
+
+	positions := cpr.getPositions()
+	positions.isGrandfathered = p.isGrandfatheredTag(tag)
+	rendered := tag
+	if !cpr.isRenderedAs(tag, positions) {
+		var builder strings.Builder
+		builder.Grow(len(tag))
+		cpr.render(&builder)
+		rendered = builder.String()
+	}
+
+	*dst = LanguageTag{tag: rendered, positions: positions, extensions: cpr.extensions}
+	return nil
+}
+
 // ParseAndNormalize checks if a tag is "well-formed" and "valid", and then
 // canonicalizes it according to RFC 5646 section 4.5. Canonicalization includes
 // replacing deprecated tags/subtags, sorting extensions, and normalizing case.
 func (p *Parser) ParseAndNormalize(tag string) (LanguageTag, error) {
+	var builder strings.Builder
+	builder.Grow(len(tag))
+	canonicalTag, isGrandfathered, err := p.canonicalize(tag, &builder)
+	if err != nil {
+		return LanguageTag{}, err
+	}
+
+	cprFinal := p.newCanonicalParseRun(canonicalTag, false)
+	err = cprFinal.parse()
+	if err != nil {
+		return LanguageTag{}, err
+	}
+
+	positions := cprFinal.getPositions()
+	positions.isGrandfathered = isGrandfathered
+
+	return LanguageTag{
+		tag:        canonicalTag,
+		positions:  positions,
+		extensions: cprFinal.extensions,
+		validated:  true,
+	}, nil
+}
+
+// ParseAndNormalizeAll applies ParseAndNormalize to every tag of a list, such
+// as a column read from a CSV file. It returns two slices of the same length
+// as tags, in the same order: the i-th tag is parsed into the i-th
+// LanguageTag, and the i-th error is nil on success. When a tag fails to
+// parse, its LanguageTag is the zero value and its error is non-nil, and the
+// other tags are still processed.
+//
+// Each canonical form gets its own allocation, so that keeping one
+// LanguageTag of a large batch does not retain the others.
+func (p *Parser) ParseAndNormalizeAll(tags []string) ([]LanguageTag, []error) {
+	parsed := make([]LanguageTag, len(tags))
+	errs := make([]error, len(tags))
+	for i, tag := range tags {
+		parsed[i], errs[i] = p.ParseAndNormalize(tag)
+	}
+	return parsed, errs
+}
+
+// Canonicalize is like ParseAndNormalize but only returns the canonical form
+// of the tag, e.g., "sr-Latn-RS" for "SR-LATN-rs". Since no LanguageTag is
+// built, the canonical tag is not parsed again, which makes it cheaper for
+// bulk normalization, such as the values of a database column.
+func (p *Parser) Canonicalize(tag string) (string, error) {
+	var builder strings.Builder
+	builder.Grow(len(tag))
+	canonicalTag, _, err := p.canonicalize(tag, &builder)
+	return canonicalTag, err
+}
+
+// canonicalize validates tag and renders its canonical form into builder, as
+// the first step of ParseAndNormalize. It also reports whether
+// the tag is a grandfathered tag without a preferred value.
+func (p *Parser) canonicalize(tag string, builder *strings.Builder) (string, bool, error) {
 	lowerInput := strings.ToLower(tag)
 	isGrandfathered := false
 	checkValidity := true
@@ -135,27 +256,96 @@ func (p *Parser) ParseAndNormalize(tag string) (LanguageTag, error) {
 	}
 
 	cpr := p.newCanonicalParseRun(tag, checkValidity)
-	err := cpr.parse()
-	if err != nil {
-		return LanguageTag{}, err
+	if err := cpr.parse(); err != nil {
+		return "", false, err
 	}
 	cpr.canonicalize()
 
-	var builder strings.Builder
-	builder.Grow(len(tag))
-	cpr.render(&builder)
-	canonicalTag := builder.String()
+	cpr.render(builder)
+	return builder.String(), isGrandfathered, nil
+}
 
-	cprFinal := p.newCanonicalParseRun(canonicalTag, false)
-	err = cprFinal.parse()
+// ValidateStrict is like ParseAndNormalize, but additionally checks that each
+// variant subtag is used in the context given by its Prefix fields in the
+// registry (RFC 5646, Section 3.1.8). For instance, "nedis" has the prefix
+// "sl", so "sl-nedis" is accepted while "de-nedis" is rejected with
+// ErrVariantPrefixMismatch.
+//
+// RFC 5646 only recommends that a variant be used with one of its prefixes,
+// and ParseAndNormalize accepts such tags as valid. This stricter check is
+// therefore opt-in. A tag satisfies a prefix if it has the same primary
+// language and contains every other subtag of the prefix, in any position
+// before the extensions, so that "sl-IT-rozaj-biske" satisfies the prefix
+// "sl-rozaj" of "biske". Variants without a Prefix field can be used with any
+// tag.
+func (p *Parser) ValidateStrict(tag string) (LanguageTag, error) {
+	lt, err := p.ParseAndNormalize(tag)
 	if err != nil {
 		return LanguageTag{}, err
 	}
+	if lt.IsGrandfathered() {
+		return lt, nil
+	}
 
-	positions := cprFinal.getPositions()
-	positions.isGrandfathered = isGrandfathered
+	subtags := strings.Split(strings.ToLower(lt.tag[:lt.positions.variantEnd]), "-")
+	for _, variant := range lt.VariantSubtags() {
+		rec, ok := p.registry.Records["variant:"+strings.ToLower(variant)]
+		if !ok || len(rec.Prefix) == 0 {
+			continue
+		}
+		satisfied := func(prefix string) bool { return hasVariantPrefix(subtags, prefix) }
+		if !slices.ContainsFunc(rec.Prefix, satisfied) {
+			return LanguageTag{}, fmt.Errorf("%w: %q requires one of %q", ErrVariantPrefixMismatch, variant, rec.Prefix)
+		}
+	}
+	return lt, nil
+}
 
-	return LanguageTag{tag: canonicalTag, positions: positions, extensions: cprFinal.extensions}, nil
+// hasVariantPrefix checks if the lowercase subtags of a tag, up to its
+// variants, satisfy a variant prefix: the primary languages must be equal, and
+// every other subtag of the prefix must be present in the tag.
+func hasVariantPrefix(subtags []string, prefix string) bool {
+	for i, sub := range strings.Split(strings.ToLower(prefix), "-") {
+		if i == 0 && sub != subtags[0] {
+			return false
+		}
+		if i > 0 && !slices.Contains(subtags[1:], sub) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsWellFormed reports whether a tag is "well-formed" as defined in RFC 5646,
+// Section 2.2.9, i.e., whether Parse would accept it. It only checks the syntax
+// of the tag, without any registry lookup, and does not build a LanguageTag.
+func (p *Parser) IsWellFormed(tag string) bool {
+	for _, r := range tag {
+		if !isLangtagChar(r) {
+			return false
+		}
+	}
+	return p.newCanonicalParseRun(tag, false).parse() == nil
+}
+
+// IsValid reports whether a tag is both "well-formed" and "valid" as defined in
+// RFC 5646, Section 2.2.9, i.e., whether ParseAndNormalize would accept it.
+// Unlike IsWellFormed, it looks every subtag up in the registry, but it skips
+// the canonicalization and rendering steps of ParseAndNormalize.
+func (p *Parser) IsValid(tag string) bool {
+	for _, r := range tag {
+		if !isLangtagChar(r) {
+			return false
+		}
+	}
+	if record, ok := p.registry.Records[strings.ToLower(tag)]; ok && record.IsGrandfathered() {
+		if record.PreferredValue != "" {
+			tag = record.PreferredValue
+		} else if record.Type == "grandfathered" {
+			return true
+		}
+	}
+	return p.newCanonicalParseRun(tag, true).parse() == nil
 }
 
 // ToExtlangForm converts a canonical language tag into its "extlang form"
@@ -207,6 +397,53 @@ func (p *Parser) ToExtlangForm(lt LanguageTag) (LanguageTag, error) {
 	}, nil
 }
 
+// ToPrimaryForm is the inverse of ToExtlangForm: it converts a tag in "extlang
+// form" into its canonical form, as described in RFC 5646, Section 4.5. If the
+// extlang subtag is registered with a Preferred-Value and a Prefix matching the
+// primary language, the primary language and the extlang are replaced by that
+// value; "zh-yue-HK" becomes "yue-HK". Only this rule is applied, unlike
+// ParseAndNormalize, which also replaces deprecated subtags, reorders variants
+// and so on.
+//
+// A tag without an extlang subtag, or whose extlang does not meet these
+// conditions, is returned unmodified. An error is returned if the resulting
+// tag is not well-formed, which can only happen with a corrupted registry.
+func (p *Parser) ToPrimaryForm(lt LanguageTag) (LanguageTag, error) {
+	if _, ok := lt.ExtendedLanguage(); !ok {
+		return lt, nil
+	}
+
+	cpr := p.newCanonicalParseRun(lt.String(), false)
+	if err := cpr.parse(); err != nil {
+		return LanguageTag{}, err
+	}
+	cpr.canonicalizeExtlangToPrimary()
+
+	var builder strings.Builder
+	builder.Grow(len(lt.tag))
+	cpr.render(&builder)
+	newTagStr := builder.String()
+	if newTagStr == lt.tag {
+		return lt, nil
+	}
+
+	// The tag is parsed again, as the Preferred-Value comes from the registry
+	// and the positions of the components have changed.
+	cpr = p.newCanonicalParseRun(newTagStr, false)
+	if err := cpr.parse(); err != nil {
+		return LanguageTag{}, err
+	}
+
+	builder.Reset()
+	cpr.render(&builder)
+
+	return LanguageTag{
+		tag:        builder.String(),
+		positions:  cpr.getPositions(),
+		extensions: cpr.extensions,
+	}, nil
+}
+
 // String returns the underlying language tag string. It implements the fmt.Stringer interface.
 func (lt *LanguageTag) String() string {
 	return lt.tag
@@ -217,6 +454,19 @@ func (lt *LanguageTag) AsStr() string {
 	return lt.tag
 }
 
+// AppendTo appends the language tag to dst and returns the extended slice,
+// e.g., to build a Content-Language header without intermediate strings.
+func (lt *LanguageTag) AppendTo(dst []byte) []byte {
+	return append(dst, lt.tag...)
+}
+
+// WriteTo implements the io.WriterTo interface, writing the language tag to w
+// in a single call.
+func (lt *LanguageTag) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, lt.tag)
+	return int64(n), err
+}
+
 // PrimaryLanguage returns the primary language subtag.
 func (lt *LanguageTag) PrimaryLanguage() string {
 	return lt.tag[:lt.positions.languageEnd]
@@ -277,6 +527,21 @@ func (lt *LanguageTag) VariantSubtags() []string {
 	return strings.Split(v, "-")
 }
 
+// HasVariant reports whether one of the variant subtags of the tag is the
+// given name, compared case-insensitively.
+func (lt *LanguageTag) HasVariant(name string) bool {
+	v, ok := lt.Variant()
+	if !ok {
+		return false
+	}
+	for variant := range strings.SplitSeq(v, "-") {
+		if strings.EqualFold(variant, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // Extension represents a single extension in a language tag, e.g., `-u-co-phonebk`.
 type Extension struct {
 	Singleton rune
@@ -293,6 +558,21 @@ func (lt *LanguageTag) ExtensionSubtags() []Extension {
 	return exts
 }
 
+// Extension returns the value of the extension introduced by the given
+// singleton (e.g., `islamcal` for 'u' in `en-US-u-islamcal`) and a boolean
+// indicating whether the tag has such an extension. The singleton is matched
+// case-insensitively.
+func (lt *LanguageTag) Extension(singleton rune) (string, bool) {
+	return lt.extensionValue(unicode.ToLower(singleton))
+}
+
+// HasExtension reports whether the tag has an extension introduced by the
+// given singleton, matched case-insensitively.
+func (lt *LanguageTag) HasExtension(singleton rune) bool {
+	_, ok := lt.Extension(singleton)
+	return ok
+}
+
 // PrivateUse returns the private use subtags as a single string (e.g., `phonebk-sort`).
 func (lt *LanguageTag) PrivateUse() (string, bool) {
 	if strings.HasPrefix(lt.tag, "x-") || strings.HasPrefix(lt.tag, "X-") {
@@ -323,6 +603,37 @@ func (lt *LanguageTag) IsGrandfathered() bool {
 	return lt.positions.isGrandfathered
 }
 
+// IsValid reports whether the tag was validated against the registry when it
+// was created, i.e., whether it comes from ParseAndNormalize or one of the
+// functions built on it, such as ValidateStrict, FromPOSIX, UnmarshalText,
+// UnmarshalJSON and Scan. Tags from Parse, ParseTo, ParseLenient or a
+// Builder, as well as tags derived from another tag (e.g., with WithRegion),
+// are only known to be well-formed, and IsValid returns false for them even
+// if they happen to be valid. Parser.IsValid can be used to check them.
+func (lt *LanguageTag) IsValid() bool {
+	return lt.validated
+}
+
+// Equal reports whether two tags are written the same way, ignoring case, as
+// language tags are case-insensitive (RFC 5646, Section 2.1.1). It does not
+// consult the registry, so tags that are only equivalent once canonicalized,
+// such as "iw" and "he", are not equal; use Parser.CanonicalEqual for that.
+func (lt *LanguageTag) Equal(other LanguageTag) bool {
+	return strings.EqualFold(lt.tag, other.tag)
+}
+
+// Components returns the main parts of the tag as plain values, which is
+// convenient for assertions and logging since LanguageTag itself has
+// unexported fields. lang is the full language, including any extended
+// language subtags, and missing parts are empty. The returned slices are
+// fresh copies, so modifying them does not affect the tag. Extensions are
+// available separately through ExtensionSubtags.
+func (lt *LanguageTag) Components() (string, string, string, []string, []string) {
+	script, _ := lt.Script()
+	region, _ := lt.Region()
+	return lt.FullLanguage(), script, region, lt.VariantSubtags(), lt.PrivateUseSubtags()
+}
+
 // MarshalJSON implements the json.Marshaler interface. It marshals the language
 // tag as a JSON string.
 func (lt *LanguageTag) MarshalJSON() ([]byte, error) {
@@ -332,27 +643,41 @@ func (lt *LanguageTag) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON implements the json.Unmarshaler interface. It performs a full
 // validity check on the tag from the JSON string.
 //
-// Performance Warning: This method creates a new parser by calling NewParser()
-// on every invocation, which is an expensive operation. For performance-critical
-// applications, it is highly recommended to unmarshal into a string and then use a
-// pre-initialized, long-lived parser instance to parse the tag.
+// The tag is parsed with the shared parser returned by Default, which is only
+// created on the first call.
 func (lt *LanguageTag) UnmarshalJSON(data []byte) error {
 	var s string
 	if err := json.Unmarshal(data, &s); err != nil {
 		return err
 	}
+	return lt.UnmarshalText([]byte(s))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. It returns the
+// language tag string, which is empty for the zero value.
+func (lt *LanguageTag) MarshalText() ([]byte, error) {
+	return []byte(lt.tag), nil
+}
 
-	if s == "" {
+// UnmarshalText implements the encoding.TextUnmarshaler interface, which makes
+// LanguageTag usable with flag.TextVar or configuration libraries. Like
+// UnmarshalJSON, it performs a full validity check and canonicalizes the tag
+// with ParseAndNormalize. An empty text yields the zero value.
+//
+// The tag is parsed with the shared parser returned by Default, which is only
+// created on the first call.
+func (lt *LanguageTag) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
 		*lt = LanguageTag{}
 		return nil
 	}
 
-	p, err := NewParser()
+	p, err := Default()
 	if err != nil {
 		return err
 	}
 
-	parsed, err := p.ParseAndNormalize(s)
+	parsed, err := p.ParseAndNormalize(string(text))
 	if err != nil {
 		return err
 	}
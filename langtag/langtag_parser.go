@@ -35,6 +35,10 @@ const (
 	minVariantLenDigit  = 4 // Min length of a variant starting with a digit.
 )
 
+// parseToLookup is the maximum length of a tag that ParseTo lowercases in a
+// stack buffer to look it up in the registry.
+const parseToLookup = 32
+
 // tagElementsPositions stores the calculated end positions of each major
 // component within the final language tag string.
 type tagElementsPositions struct {
@@ -395,6 +399,7 @@ func (cpr *canonicalParseRun) canonicalize() {
 	cpr.canonicalizeVariantOrder()
 	cpr.canonicalizeScriptSuppression()
 	cpr.canonicalizeExtensionOrder()
+	cpr.canonicalizeUnicodeExtension()
 }
 
 // canonicalizeExtlangToPrimary replaces an extlang with its preferred primary language subtag.
@@ -494,9 +499,7 @@ func (cpr *canonicalParseRun) canonicalizeScriptSuppression() {
 	if cpr.script == "" {
 		return
 	}
-	key := "language:" + strings.ToLower(cpr.language)
-	langRec, ok := cpr.parent.registry.Records[key]
-	if ok && langRec.SuppressScript != "" && strings.EqualFold(cpr.script, langRec.SuppressScript) {
+	if script, ok := cpr.parent.SuppressScript(cpr.language); ok && strings.EqualFold(cpr.script, script) {
 		cpr.script = ""
 	}
 }
@@ -510,6 +513,20 @@ func (cpr *canonicalParseRun) canonicalizeExtensionOrder() {
 	}
 }
 
+// canonicalizeUnicodeExtension puts the content of the 'u' extension in its
+// canonical form. An extension that does not follow the UTS #35 syntax is left
+// untouched.
+func (cpr *canonicalParseRun) canonicalizeUnicodeExtension() {
+	for i := range cpr.extensions {
+		if cpr.extensions[i].Singleton != unicodeSingleton {
+			continue
+		}
+		if value, ok := canonicalUnicodeExtension(cpr.extensions[i].Value); ok {
+			cpr.extensions[i].Value = value
+		}
+	}
+}
+
 // render reconstructs the language tag string from the parsed components.
 func (cpr *canonicalParseRun) render(b *strings.Builder) {
 	if cpr.language != "" {
@@ -557,6 +574,61 @@ func (cpr *canonicalParseRun) render(b *strings.Builder) {
 	}
 }
 
+// isGrandfatheredTag checks if a whole tag is a grandfathered or redundant tag
+// of the registry. Short tags are lowercased in a stack buffer, which avoids
+// allocating the lookup key.
+func (p *Parser) isGrandfatheredTag(tag string) bool {
+	var record Record
+	var ok bool
+	if len(tag) <= parseToLookup {
+		var buf [parseToLookup]byte
+		for i := range len(tag) {
+			c := tag[i]
+			if c >= 'A' && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			buf[i] = c
+		}
+		record, ok = p.registry.Records[string(buf[:len(tag)])]
+	} else {
+		record, ok = p.registry.Records[strings.ToLower(tag)]
+	}
+	return ok && record.IsGrandfathered()
+}
+
+// isRenderedAs reports whether render would produce exactly the given tag,
+// which is the parsed input. This is the case when the input has no trailing
+// hyphen or dangling private use singleton, and when each of its components
+// already has the case render gives it: title case for the script, uppercase
+// for the region and lowercase for everything else.
+func (cpr *canonicalParseRun) isRenderedAs(tag string, pos tagElementsPositions) bool {
+	renderedLen := pos.extensionEnd
+	if len(cpr.privateuse) > 0 {
+		if cpr.language != "" {
+			renderedLen += len("-x")
+		} else {
+			renderedLen += len("x")
+		}
+		for _, subtag := range cpr.privateuse {
+			renderedLen += 1 + len(subtag)
+		}
+	}
+	if renderedLen != len(tag) {
+		return false
+	}
+
+	if !isLowerASCII(tag[:pos.extlangEnd]) || !isLowerASCII(tag[pos.regionEnd:]) {
+		return false
+	}
+	if pos.scriptEnd > pos.extlangEnd {
+		script := tag[pos.extlangEnd+1 : pos.scriptEnd]
+		if script[0] < 'A' || script[0] > 'Z' || !isLowerASCII(script[1:]) {
+			return false
+		}
+	}
+	return !hasLowerASCII(tag[pos.scriptEnd:pos.regionEnd])
+}
+
 // getPositions calculates the final end positions of each component in the
 // rendered tag string.
 func (cpr *canonicalParseRun) getPositions() tagElementsPositions {
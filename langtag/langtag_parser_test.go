@@ -374,6 +374,48 @@ func TestCanonicalizeExtensionOrder(t *testing.T) {
 	}
 }
 
+// TestCanonicalizeUnicodeExtension verifies that the content of the 'u'
+// extension is canonicalized as required by UTS #35, Section 3.2.1.
+func TestCanonicalizeUnicodeExtension(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "Keywords sorted by key", value: "nu-latn-co-phonebk-ca-gregory", want: "ca-gregory-co-phonebk-nu-latn"},
+		{name: "Attributes sorted and deduplicated", value: "zzz-aaa-zzz-nu-thai", want: "aaa-zzz-nu-thai"},
+		{
+			name:  "Multi-subtag types kept together",
+			value: "nu-arab-ca-islamic-umalqura",
+			want:  "ca-islamic-umalqura-nu-arab",
+		},
+		{name: "Deprecated type replaced", value: "ca-islamicc", want: "ca-islamic-civil"},
+		{name: "Deprecated type replaced with true", value: "kn-yes-ks-primary", want: "kn-ks-level1"},
+		{name: "True type omitted", value: "kn-true", want: "kn"},
+		{name: "Repeated key dropped", value: "ca-buddhist-ca-gregory", want: "ca-buddhist"},
+		{name: "Invalid extension untouched", value: "a1-foo", want: "a1-foo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpr := &canonicalParseRun{
+				extensions: []Extension{
+					{Singleton: 'a', Value: "nu-latn-ca-gregory"},
+					{Singleton: 'u', Value: tt.value},
+				},
+			}
+			cpr.canonicalizeUnicodeExtension()
+
+			if got := cpr.extensions[1].Value; got != tt.want {
+				t.Errorf("canonicalizeUnicodeExtension() = %q, want %q", got, tt.want)
+			}
+			if got := cpr.extensions[0].Value; got != "nu-latn-ca-gregory" {
+				t.Errorf("canonicalizeUnicodeExtension() modified another extension: %q", got)
+			}
+		})
+	}
+}
+
 // TestCanonicalizeScriptSuppression ensures redundant script subtags are removed,
 // as specified in RFC 5646, Section 3.1.9. This process simplifies tags by
 // omitting the script when it is the default for a language (e.g., 'Latn' for English).
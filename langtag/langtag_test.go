@@ -20,11 +20,15 @@ package langtag
 import (
 	"encoding/json"
 	"errors"
+	"flag"
+	"io"
 	"log/slog"
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"unsafe"
 )
 
 //nolint:gochecknoglobals // p is a global parser instance, initialized once by TestMain to speed up tests.
@@ -81,6 +85,45 @@ func TestLanguageTag_AsStr(t *testing.T) {
 	}
 }
 
+// TestLanguageTag_AppendTo tests that appending tags produces the same result
+// as joining their String() values.
+func TestLanguageTag_AppendTo(t *testing.T) {
+	tags := []LanguageTag{
+		mustParseAndNormalize(t, "en-US"),
+		mustParseAndNormalize(t, "zh-Hant-TW"),
+		mustParseAndNormalize(t, "de-CH-1996-x-phonebk"),
+		{},
+	}
+
+	var buf []byte
+	strs := make([]string, 0, len(tags))
+	for i := range tags {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = tags[i].AppendTo(buf)
+		strs = append(strs, tags[i].String())
+	}
+	if want := strings.Join(strs, ", "); string(buf) != want {
+		t.Errorf("AppendTo() produced %q, want %q", buf, want)
+	}
+}
+
+// TestLanguageTag_WriteTo tests writing a tag to an io.Writer.
+func TestLanguageTag_WriteTo(t *testing.T) {
+	var _ io.WriterTo = (*LanguageTag)(nil)
+
+	lt := mustParseAndNormalize(t, "sr-Latn-RS")
+	var b strings.Builder
+	n, err := lt.WriteTo(&b)
+	if err != nil {
+		t.Fatalf("WriteTo() returned an unexpected error: %v", err)
+	}
+	if b.String() != "sr-Latn-RS" || n != int64(len("sr-Latn-RS")) {
+		t.Errorf("WriteTo() = (%d, %q), want (%d, %q)", n, b.String(), len("sr-Latn-RS"), "sr-Latn-RS")
+	}
+}
+
 // TestLanguageTag_PrimaryLanguage tests the PrimaryLanguage() method.
 // RFC 5646 Section 2.2.1 defines the primary language subtag as the first subtag.
 func TestLanguageTag_PrimaryLanguage(t *testing.T) {
@@ -426,6 +469,62 @@ func TestLanguageTag_ExtensionSubtags(t *testing.T) {
 	}
 }
 
+// TestLanguageTag_Extension tests the Extension() method.
+func TestLanguageTag_Extension(t *testing.T) {
+	tests := []struct {
+		name      string
+		tag       string
+		singleton rune
+		want      string
+		wantOk    bool
+	}{
+		{name: "Present", tag: "en-US-u-islamcal", singleton: 'u', want: "islamcal", wantOk: true},
+		{name: "Absent", tag: "en-US-u-islamcal", singleton: 't', want: "", wantOk: false},
+		{name: "Uppercase singleton", tag: "en-US-u-islamcal", singleton: 'U', want: "islamcal", wantOk: true},
+		{name: "Among several", tag: "zh-CN-a-myext-b-another", singleton: 'b', want: "another", wantOk: true},
+		{name: "Private use is not an extension", tag: "en-x-foo", singleton: 'x', want: "", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			got, ok := lt.Extension(tt.singleton)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Extension(%q) = (%q, %v), want (%q, %v)", tt.singleton, got, ok, tt.want, tt.wantOk)
+			}
+			if has := lt.HasExtension(tt.singleton); has != tt.wantOk {
+				t.Errorf("HasExtension(%q) = %v, want %v", tt.singleton, has, tt.wantOk)
+			}
+		})
+	}
+}
+
+// TestLanguageTag_HasVariant tests the HasVariant() method.
+func TestLanguageTag_HasVariant(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		variant string
+		want    bool
+	}{
+		{name: "First variant", tag: "sl-rozaj-biske", variant: "rozaj", want: true},
+		{name: "Last variant", tag: "sl-rozaj-biske", variant: "biske", want: true},
+		{name: "Case-insensitive", tag: "sl-rozaj-biske", variant: "BISKE", want: true},
+		{name: "Absent variant", tag: "sl-rozaj-biske", variant: "nedis", want: false},
+		{name: "Prefix of a variant", tag: "sl-rozaj-biske", variant: "bis", want: false},
+		{name: "No variants", tag: "en-US", variant: "biske", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			if got := lt.HasVariant(tt.variant); got != tt.want {
+				t.Errorf("HasVariant(%q) = %v, want %v", tt.variant, got, tt.want)
+			}
+		})
+	}
+}
+
 // TestLanguageTag_PrivateUse tests the PrivateUse() method.
 // RFC 5646 Section 2.2.7 defines private use subtags, starting with 'x'.
 // Examples from RFC Appendix A: de-CH-x-phonebk, x-whatever.
@@ -549,6 +648,145 @@ func TestLanguageTag_IsGrandfathered(t *testing.T) {
 	}
 }
 
+// TestLanguageTag_Equal tests the case-insensitive comparison of tags, which
+// does not involve the registry.
+func TestLanguageTag_Equal(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "Identical", a: "en-US", b: "en-US", want: true},
+		{name: "Different case", a: "EN-us", b: "en-US", want: true},
+		{name: "Different region", a: "en-US", b: "en-GB", want: false},
+		{name: "Prefix only", a: "en", b: "en-US", want: false},
+		{name: "Deprecated subtag not resolved", a: "iw", b: "he", want: false},
+		{name: "Extlang not resolved", a: "zh-cmn", b: "cmn", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := mustParse(t, tt.a)
+			if got := a.Equal(mustParse(t, tt.b)); got != tt.want {
+				t.Errorf("%q.Equal(%q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+
+	var zero LanguageTag
+	if !zero.Equal(LanguageTag{}) {
+		t.Error("the zero LanguageTag is not equal to itself")
+	}
+}
+
+// TestLanguageTag_Components tests the exported snapshot of a tag's parts.
+func TestLanguageTag_Components(t *testing.T) {
+	tests := []struct {
+		tag                   string
+		lang, script, region  string
+		variants, privateUses []string
+	}{
+		{
+			tag: "zh-yue-Hant-HK-x-phonebk", lang: "zh-yue", script: "Hant", region: "HK",
+			privateUses: []string{"phonebk"},
+		},
+		{tag: "sl-rozaj-biske", lang: "sl", variants: []string{"rozaj", "biske"}},
+		{tag: "en-u-ca-gregory", lang: "en"},
+		{tag: "x-private", lang: "", privateUses: []string{"private"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			lang, script, region, variants, privateUses := lt.Components()
+			if lang != tt.lang || script != tt.script || region != tt.region {
+				t.Errorf("Components() = (%q, %q, %q), want (%q, %q, %q)",
+					lang, script, region, tt.lang, tt.script, tt.region)
+			}
+			if !reflect.DeepEqual(variants, tt.variants) {
+				t.Errorf("Components() variants = %v, want %v", variants, tt.variants)
+			}
+			if !reflect.DeepEqual(privateUses, tt.privateUses) {
+				t.Errorf("Components() private use = %v, want %v", privateUses, tt.privateUses)
+			}
+		})
+	}
+
+	t.Run("Returned slices are copies", func(t *testing.T) {
+		lt := mustParse(t, "sl-rozaj-x-abc")
+		_, _, _, variants, privateUses := lt.Components()
+		variants[0] = "mutated"
+		privateUses[0] = "mutated"
+		_, _, _, variants, privateUses = lt.Components()
+		if variants[0] != "rozaj" || privateUses[0] != "abc" || lt.String() != "sl-rozaj-x-abc" {
+			t.Errorf("mutating the returned slices changed the tag to %q", lt.String())
+		}
+	})
+}
+
+// TestLanguageTag_IsValid tests that only the entry points checking the tag
+// against the registry mark it as validated.
+func TestLanguageTag_IsValid(t *testing.T) {
+	fromParse := func(tag string) (LanguageTag, error) { return p.Parse(tag) }
+	fromParseTo := func(tag string) (LanguageTag, error) {
+		var lt LanguageTag
+		err := p.ParseTo(tag, &lt)
+		return lt, err
+	}
+	fromJSON := func(tag string) (LanguageTag, error) {
+		var lt LanguageTag
+		err := json.Unmarshal([]byte(`"`+tag+`"`), &lt)
+		return lt, err
+	}
+	fromText := func(tag string) (LanguageTag, error) {
+		var lt LanguageTag
+		err := lt.UnmarshalText([]byte(tag))
+		return lt, err
+	}
+	fromScan := func(tag string) (LanguageTag, error) {
+		var lt LanguageTag
+		err := lt.Scan(tag)
+		return lt, err
+	}
+	fromWithRegion := func(tag string) (LanguageTag, error) {
+		lt := mustParseAndNormalize(t, tag)
+		return lt.WithRegion("GB")
+	}
+
+	tests := []struct {
+		name  string
+		parse func(string) (LanguageTag, error)
+		want  bool
+	}{
+		{name: "Parse", parse: fromParse, want: false},
+		{name: "ParseTo", parse: fromParseTo, want: false},
+		{name: "ParseLenient", parse: p.ParseLenient, want: false},
+		{name: "ParseAndNormalize", parse: p.ParseAndNormalize, want: true},
+		{name: "ValidateStrict", parse: p.ValidateStrict, want: true},
+		{name: "UnmarshalJSON", parse: fromJSON, want: true},
+		{name: "UnmarshalText", parse: fromText, want: true},
+		{name: "Scan", parse: fromScan, want: true},
+		{name: "Derived tag", parse: fromWithRegion, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lt, err := tt.parse("en-US")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := lt.IsValid(); got != tt.want {
+				t.Errorf("IsValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var zero LanguageTag
+	if zero.IsValid() {
+		t.Error("IsValid() = true for the zero LanguageTag, want false")
+	}
+}
+
 // TestLanguageTag_MarshalJSON tests the MarshalJSON method.
 func TestLanguageTag_MarshalJSON(t *testing.T) {
 	tests := []struct {
@@ -652,12 +890,13 @@ func TestLanguageTag_UnmarshalJSON(t *testing.T) {
 	}
 
 	t.Run("NewParser failure", func(t *testing.T) {
-		originalData := embeddedRegistryData
+		originalData, originalParser := embeddedRegistryData, defaultParser
 		t.Cleanup(func() {
-			embeddedRegistryData = originalData
+			embeddedRegistryData, defaultParser = originalData, originalParser
 		})
 
 		embeddedRegistryData = []byte{}
+		defaultParser = sync.OnceValues(newDefaultParser)
 
 		var lt LanguageTag
 		jsonData := []byte(`"en-US"`)
@@ -674,6 +913,97 @@ func TestLanguageTag_UnmarshalJSON(t *testing.T) {
 	})
 }
 
+// TestLanguageTag_MarshalText tests the MarshalText method.
+func TestLanguageTag_MarshalText(t *testing.T) {
+	tests := []struct {
+		name string
+		lt   LanguageTag
+		want string
+	}{
+		{name: "Valid tag", lt: mustParseAndNormalize(t, "de-CH"), want: "de-CH"},
+		{name: "Empty tag", lt: LanguageTag{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.lt.MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLanguageTag_UnmarshalText tests the UnmarshalText method, which must
+// validate and canonicalize the tag like UnmarshalJSON does.
+func TestLanguageTag_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantTag string
+		wantErr bool
+	}{
+		{name: "Valid tag", text: "en-US", wantTag: "en-US"},
+		{name: "Canonicalization applied", text: "art-lojban", wantTag: "jbo"},
+		{name: "Case normalization applied", text: "sR-lAtN-rs", wantTag: "sr-Latn-RS"},
+		{name: "Invalid tag", text: "123-bogus", wantErr: true},
+		{name: "Empty text", text: "", wantTag: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lt := mustParse(t, "fr")
+			err := lt.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				if got := lt.String(); got != tt.wantTag {
+					t.Errorf("UnmarshalText() got tag %q, want %q", got, tt.wantTag)
+				}
+			}
+		})
+	}
+
+	t.Run("flag.TextVar", func(t *testing.T) {
+		var lt LanguageTag
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.TextVar(&lt, "lang", &LanguageTag{}, "language")
+		if err := fs.Parse([]string{"-lang", "iw-IL"}); err != nil {
+			t.Fatalf("flag parsing failed: %v", err)
+		}
+		if got := lt.String(); got != "he-IL" {
+			t.Errorf("flag value = %q, want %q", got, "he-IL")
+		}
+	})
+
+	t.Run("NewParser failure", func(t *testing.T) {
+		originalData, originalParser := embeddedRegistryData, defaultParser
+		t.Cleanup(func() {
+			embeddedRegistryData, defaultParser = originalData, originalParser
+		})
+
+		embeddedRegistryData = []byte{}
+		defaultParser = sync.OnceValues(newDefaultParser)
+
+		var lt LanguageTag
+		err := lt.UnmarshalText([]byte("en-US"))
+
+		if err == nil {
+			t.Fatal("UnmarshalText() did not return an error, but was expected to")
+		}
+
+		wantErrMsg := "embedded language-subtag-registry file is empty or not found"
+		if err.Error() != wantErrMsg {
+			t.Errorf("UnmarshalText() error = %q, want %q", err, wantErrMsg)
+		}
+	})
+}
+
 // TestParser_Parse tests the non-validating Parse method.
 // RFC 5646 Section 2.2.9 defines "well-formed" as conforming to the ABNF.
 // This test checks for well-formedness and case normalization, not validity.
@@ -725,6 +1055,42 @@ func TestParser_Parse(t *testing.T) {
 	}
 }
 
+// TestParser_ParseLenient tests that the underscore is accepted as a subtag
+// separator, while the strict Parse keeps rejecting it.
+func TestParser_ParseLenient(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		wantTag string
+		wantErr error
+	}{
+		{name: "Underscore separator", tag: "en_US", wantTag: "en-US"},
+		{name: "Case normalization", tag: "SR_latn_rs", wantTag: "sr-Latn-RS"},
+		{name: "Mixed separators", tag: "de_CH-1996", wantTag: "de-CH-1996"},
+		{name: "Hyphen separator", tag: "fr-CA", wantTag: "fr-CA"},
+		{name: "POSIX charset", tag: "en_US.UTF-8", wantErr: ErrForbiddenChar},
+		{name: "POSIX modifier", tag: "sr_RS@latin", wantErr: ErrForbiddenChar},
+		{name: "Other forbidden character", tag: "en US", wantErr: ErrForbiddenChar},
+		{name: "Empty subtag", tag: "en__US", wantErr: ErrEmptySubtag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.ParseLenient(tt.tag)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseLenient(%q) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.wantTag {
+				t.Errorf("ParseLenient(%q) = %q, want %q", tt.tag, got.String(), tt.wantTag)
+			}
+		})
+	}
+
+	if _, err := p.Parse("en_US"); !errors.Is(err, ErrForbiddenChar) {
+		t.Errorf("Parse(%q) error = %v, want %v", "en_US", err, ErrForbiddenChar)
+	}
+}
+
 // TestParser_ParseAndNormalize tests the validating and canonicalizing ParseAndNormalize method.
 // RFC 5646 Section 4.5 defines canonicalization. Section 2.2.9 defines validity.
 func TestParser_ParseAndNormalize(t *testing.T) {
@@ -743,6 +1109,9 @@ func TestParser_ParseAndNormalize(t *testing.T) {
 		{name: "Extlang canonicalization", tag: "zh-gan", wantTag: "gan"},
 		{name: "Extension reordering", tag: "en-b-ccc-a-aaa", wantTag: "en-a-aaa-b-ccc"},
 		{name: "Script suppression", tag: "is-Latn", wantTag: "is"},
+		{name: "Unicode keyword reordering", tag: "en-u-nu-latn-ca-gregory", wantTag: "en-u-ca-gregory-nu-latn"},
+		{name: "Unicode deprecated type", tag: "ar-u-ca-islamicc", wantTag: "ar-u-ca-islamic-civil"},
+		{name: "Unicode extension after reordering", tag: "en-u-kn-true-a-aaa", wantTag: "en-a-aaa-u-kn"},
 		{name: "Case canonicalization", tag: "SR-LATN-rs", wantTag: "sr-Latn-RS"},
 
 		// Validity error cases from RFC Appendix A and 2.2.9
@@ -769,6 +1138,264 @@ func TestParser_ParseAndNormalize(t *testing.T) {
 	}
 }
 
+// TestParser_Canonicalize tests that Canonicalize returns the same string, or
+// the same error, as ParseAndNormalize.
+func TestParser_Canonicalize(t *testing.T) {
+	tags := []string{
+		"zh-min-nan", "art-lojban", "i-enochian", "en-BU", "zh-gan", "en-b-ccc-a-aaa", "is-Latn",
+		"en-u-nu-latn-ca-gregory", "ar-u-ca-islamicc", "SR-LATN-rs", "x-private",
+		"zz-US", "en-BOGUS", "de-DE-1901-1901", "zh-gan-gan", "en-a-",
+	}
+
+	for _, tag := range tags {
+		t.Run(tag, func(t *testing.T) {
+			want, wantErr := p.ParseAndNormalize(tag)
+			got, err := p.Canonicalize(tag)
+			if !errors.Is(err, wantErr) || (err == nil) != (wantErr == nil) {
+				t.Fatalf("Canonicalize() error = %v, want %v", err, wantErr)
+			}
+			if got != want.String() {
+				t.Errorf("Canonicalize() = %q, want %q", got, want.String())
+			}
+		})
+	}
+}
+
+// TestParser_ParseTo tests that ParseTo produces the same tags as Parse while
+// reusing its destination.
+func TestParser_ParseTo(t *testing.T) {
+	tags := []string{
+		"de", "en-US", "EN-us", "sr-Latn-RS", "MN-cYRL-mn", "zh-yue-HK", "de-CH-1901-1996",
+		"de-CH-x-phonebk", "x-whatever", "X-Whatever", "i-klingon", "I-KLINGON", "art-lojban",
+		"zh-Hant-TW", "en-a-myext-b-another", "en-U-CA-Gregory-x-Foo", "sl-ROZAJ-biske", "es-419",
+		"en-", "en-x", "en-Latn-", "en_US", "en--US", "verylongsubtag-en", "x-", "en-a-", "en-a-b-foo",
+	}
+
+	var dst LanguageTag
+	for _, tag := range tags {
+		t.Run(tag, func(t *testing.T) {
+			want, wantErr := p.Parse(tag)
+			err := p.ParseTo(tag, &dst)
+			if !errors.Is(err, wantErr) {
+				t.Fatalf("ParseTo() error = %v, want %v", err, wantErr)
+			}
+			if dst.tag != want.tag || dst.positions != want.positions ||
+				!reflect.DeepEqual(dst.ExtensionSubtags(), want.ExtensionSubtags()) {
+				t.Errorf("ParseTo() = %+v, want %+v", dst, want)
+			}
+		})
+	}
+
+	t.Run("Extension storage is reused", func(t *testing.T) {
+		var lt LanguageTag
+		if err := p.ParseTo("en-a-aaa-b-bbb-c-ccc", &lt); err != nil {
+			t.Fatalf("ParseTo() unexpected error: %v", err)
+		}
+		storage := &lt.extensions[0]
+		if err := p.ParseTo("fr-u-nu-latn", &lt); err != nil {
+			t.Fatalf("ParseTo() unexpected error: %v", err)
+		}
+		if &lt.extensions[0] != storage {
+			t.Error("ParseTo() reallocated the extensions instead of reusing them")
+		}
+		if exts := lt.ExtensionSubtags(); len(exts) != 1 || exts[0].Value != "nu-latn" {
+			t.Errorf("ExtensionSubtags() = %v, want [{u nu-latn}]", exts)
+		}
+	})
+
+	t.Run("Extension storage is kept on error", func(t *testing.T) {
+		var lt LanguageTag
+		if err := p.ParseTo("en-a-aaa-b-bbb-c-ccc", &lt); err != nil {
+			t.Fatalf("ParseTo() unexpected error: %v", err)
+		}
+		storage := &lt.extensions[:1][0]
+		for _, invalid := range []string{"en_US", "en-a-"} {
+			if err := p.ParseTo(invalid, &lt); err == nil {
+				t.Fatalf("ParseTo(%q) succeeded, want an error", invalid)
+			}
+			if lt.String() != "" || len(lt.extensions) != 0 || &lt.extensions[:1][0] != storage {
+				t.Errorf("ParseTo(%q) left %+v, want an empty tag with the same extension storage", invalid, lt)
+			}
+		}
+		if err := p.ParseTo("fr-u-nu-latn", &lt); err != nil || &lt.extensions[0] != storage {
+			t.Errorf("ParseTo() = %v, want the extension storage to be reused after an error", err)
+		}
+	})
+
+	t.Run("Canonical input is not copied", func(t *testing.T) {
+		tag := "sr-Latn-RS"
+		var lt LanguageTag
+		if err := p.ParseTo(tag, &lt); err != nil {
+			t.Fatalf("ParseTo() unexpected error: %v", err)
+		}
+		if unsafe.StringData(lt.String()) != unsafe.StringData(tag) {
+			t.Error("ParseTo() rebuilt a tag that was already in canonical case")
+		}
+	})
+}
+
+// BenchmarkParser_ParseTo compares ParseTo with a reused destination against
+// Parse over a set of tags.
+func BenchmarkParser_ParseTo(b *testing.B) {
+	tags := []string{"en-US", "sr-Latn-RS", "de-CH-1901", "zh-Hant-TW-u-nu-hanidec", "en-x-private", "fr", "es-419"}
+	b.Run("Parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, tag := range tags {
+				_, _ = p.Parse(tag)
+			}
+		}
+	})
+	b.Run("ParseTo", func(b *testing.B) {
+		b.ReportAllocs()
+		var lt LanguageTag
+		for b.Loop() {
+			for _, tag := range tags {
+				_ = p.ParseTo(tag, &lt)
+			}
+		}
+	})
+}
+
+// TestParser_ParseAndNormalizeAll tests the batch parsing of a list mixing
+// valid, well-formed but invalid, and malformed tags.
+func TestParser_ParseAndNormalizeAll(t *testing.T) {
+	tags := []string{"EN-us", "zz-US", "iw", "en-a-", "i-klingon", "", "sr-Latn-RS", "en--US", "en-GB-oed"}
+	wantTags := []string{"en-US", "", "he", "", "tlh", "", "sr-Latn-RS", "", "en-GB-oxendict"}
+	wantErrs := []error{
+		nil, ErrInvalidLanguage, nil, ErrEmptyExtension, nil, ErrEmptySubtag, nil, ErrEmptySubtag, nil,
+	}
+
+	got, errs := p.ParseAndNormalizeAll(tags)
+	if len(got) != len(tags) || len(errs) != len(tags) {
+		t.Fatalf("ParseAndNormalizeAll() returned %d tags and %d errors, want %d of each",
+			len(got), len(errs), len(tags))
+	}
+	for i, tag := range tags {
+		if !errors.Is(errs[i], wantErrs[i]) {
+			t.Errorf("error for %q = %v, want %v", tag, errs[i], wantErrs[i])
+		}
+		if got[i].String() != wantTags[i] {
+			t.Errorf("tag for %q = %q, want %q", tag, got[i].String(), wantTags[i])
+		}
+		if errs[i] != nil && !reflect.DeepEqual(got[i], LanguageTag{}) {
+			t.Errorf("tag for %q = %#v, want the zero LanguageTag", tag, got[i])
+		}
+	}
+
+	// Each tag must match the result of a single parse.
+	for i, tag := range tags {
+		want, err := p.ParseAndNormalize(tag)
+		if err == nil && !reflect.DeepEqual(got[i], want) {
+			t.Errorf("tag for %q = %#v, want %#v", tag, got[i], want)
+		}
+	}
+
+	if got, errs = p.ParseAndNormalizeAll(nil); len(got) != 0 || len(errs) != 0 {
+		t.Errorf("ParseAndNormalizeAll(nil) = (%v, %v), want empty slices", got, errs)
+	}
+}
+
+// TestParser_ValidateStrict tests the opt-in check of the registered prefixes
+// of variant subtags.
+func TestParser_ValidateStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		wantTag string
+		wantErr error
+	}{
+		{name: "Variant with its prefix", tag: "sl-nedis", wantTag: "sl-nedis"},
+		{name: "Variant with a region between", tag: "sl-IT-nedis", wantTag: "sl-IT-nedis"},
+		{name: "Variant without its prefix", tag: "de-nedis", wantErr: ErrVariantPrefixMismatch},
+		{name: "Multi-subtag prefix", tag: "sl-rozaj-biske", wantTag: "sl-rozaj-biske"},
+		{name: "Multi-subtag prefix with a region", tag: "sl-IT-rozaj-biske", wantTag: "sl-IT-rozaj-biske"},
+		{name: "Incomplete multi-subtag prefix", tag: "sl-biske", wantErr: ErrVariantPrefixMismatch},
+		{name: "One of several prefixes", tag: "sl-rozaj-biske-1994", wantTag: "sl-rozaj-biske-1994"},
+		{name: "Variant without prefix", tag: "en-alalc97", wantTag: "en-alalc97"},
+		{name: "Canonicalized before checking", tag: "DE-ch-1901", wantTag: "de-CH-1901"},
+		{name: "No variant", tag: "en-US", wantTag: "en-US"},
+		{name: "Grandfathered", tag: "i-enochian", wantTag: "i-enochian"},
+		{name: "Invalid tag", tag: "en-zzzzz", wantErr: ErrInvalidSubtag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.ValidateStrict(tt.tag)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateStrict(%q) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.wantTag {
+				t.Errorf("ValidateStrict(%q) = %q, want %q", tt.tag, got.String(), tt.wantTag)
+			}
+		})
+	}
+
+	// The prefix is only a recommendation, so the check is opt-in.
+	if _, err := p.ParseAndNormalize("de-nedis"); err != nil {
+		t.Errorf("ParseAndNormalize(%q) returned an unexpected error: %v", "de-nedis", err)
+	}
+}
+
+// TestParser_IsWellFormedAndIsValid tests the well-formedness and validity
+// predicates against Parse and ParseAndNormalize.
+func TestParser_IsWellFormedAndIsValid(t *testing.T) {
+	tests := []struct {
+		tag            string
+		wantWellFormed bool
+		wantValid      bool
+	}{
+		{tag: "en-US", wantWellFormed: true, wantValid: true},
+		{tag: "en_US", wantWellFormed: false, wantValid: false},
+		{tag: "zz-US", wantWellFormed: true, wantValid: false},
+		{tag: "de-DE-1901-1901", wantWellFormed: true, wantValid: false},
+		{tag: "en-a-foo-a-bar", wantWellFormed: true, wantValid: false},
+		{tag: "i-klingon", wantWellFormed: true, wantValid: true},
+		{tag: "i-enochian", wantWellFormed: true, wantValid: true},
+		{tag: "zh-min-nan", wantWellFormed: false, wantValid: true},
+		{tag: "x-whatever", wantWellFormed: true, wantValid: true},
+		{tag: "en--US", wantWellFormed: false, wantValid: false},
+		{tag: "en-a-", wantWellFormed: false, wantValid: false},
+		{tag: "", wantWellFormed: false, wantValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := p.IsWellFormed(tt.tag); got != tt.wantWellFormed {
+				t.Errorf("IsWellFormed() = %v, want %v", got, tt.wantWellFormed)
+			}
+			if got := p.IsValid(tt.tag); got != tt.wantValid {
+				t.Errorf("IsValid() = %v, want %v", got, tt.wantValid)
+			}
+
+			_, parseErr := p.Parse(tt.tag)
+			if (parseErr == nil) != tt.wantWellFormed {
+				t.Errorf("IsWellFormed() disagrees with Parse() error %v", parseErr)
+			}
+			_, normalizeErr := p.ParseAndNormalize(tt.tag)
+			if (normalizeErr == nil) != tt.wantValid {
+				t.Errorf("IsValid() disagrees with ParseAndNormalize() error %v", normalizeErr)
+			}
+		})
+	}
+}
+
+// BenchmarkParser_IsValid compares the validity predicate with ParseAndNormalize.
+func BenchmarkParser_IsValid(b *testing.B) {
+	b.Run("IsValid", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			p.IsValid("sr-Latn-RS-u-nu-latn")
+		}
+	})
+	b.Run("ParseAndNormalize", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_, _ = p.ParseAndNormalize("sr-Latn-RS-u-nu-latn")
+		}
+	})
+}
+
 // TestParser_ToExtlangForm tests converting a canonical tag to its extlang form.
 // RFC 5646 Section 4.5 defines the 'extlang form'.
 func TestParser_ToExtlangForm(t *testing.T) {
@@ -879,6 +1506,89 @@ Preferred-Value: en--US
 	}
 }
 
+// TestParser_ToPrimaryForm tests converting a tag in extlang form to its
+// primary language form, mirroring TestCanonicalizeExtlangToPrimary.
+func TestParser_ToPrimaryForm(t *testing.T) {
+	testParser := newTestParser(map[string]Record{
+		"language:zh": {Type: "language", Subtag: "zh"},
+		"language:ar": {Type: "language", Subtag: "ar"},
+		"extlang:cmn": {Type: "extlang", Subtag: "cmn", Prefix: []string{"zh"}, PreferredValue: "cmn"},
+		"extlang:gan": {Type: "extlang", Subtag: "gan", Prefix: []string{"zh"}},
+		"extlang:aao": {Type: "extlang", Subtag: "aao", Prefix: []string{"ar"}, PreferredValue: "aao"},
+	})
+
+	tests := []struct {
+		name   string
+		parser *Parser
+		tag    string
+		want   string
+	}{
+		{name: "Extlang to primary", parser: testParser, tag: "zh-cmn", want: "cmn"},
+		{name: "Subtags after the extlang are kept", parser: testParser, tag: "zh-cmn-Hans-CN", want: "cmn-Hans-CN"},
+		{name: "No extlang", parser: testParser, tag: "zh-CN", want: "zh-CN"},
+		{name: "Extlang without Preferred-Value", parser: testParser, tag: "zh-gan", want: "zh-gan"},
+		{name: "Mismatched prefix", parser: testParser, tag: "zh-aao", want: "zh-aao"},
+		{name: "Unregistered extlang", parser: testParser, tag: "zh-zzz", want: "zh-zzz"},
+		{name: "Embedded registry", parser: p, tag: "zh-yue-HK", want: "yue-HK"},
+		{name: "Extension and private use", parser: p, tag: "zh-yue-u-co-stroke-x-foo", want: "yue-u-co-stroke-x-foo"},
+		{name: "Redundant tag", parser: p, tag: "zh-cmn-Hans", want: "cmn-Hans"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lt, err := tt.parser.Parse(tt.tag)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned an unexpected error: %v", tt.tag, err)
+			}
+			got, err := tt.parser.ToPrimaryForm(lt)
+			if err != nil {
+				t.Fatalf("ToPrimaryForm(%q) returned an unexpected error: %v", tt.tag, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ToPrimaryForm(%q) = %q, want %q", tt.tag, got.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("Inverse of ToExtlangForm", func(t *testing.T) {
+		canonical := mustParseAndNormalize(t, "hak-CN")
+		extlang, err := p.ToExtlangForm(canonical)
+		if err != nil {
+			t.Fatalf("ToExtlangForm() returned an unexpected error: %v", err)
+		}
+		got, err := p.ToPrimaryForm(extlang)
+		if err != nil {
+			t.Fatalf("ToPrimaryForm() returned an unexpected error: %v", err)
+		}
+		if got.String() != canonical.String() {
+			t.Errorf("ToPrimaryForm(%q) = %q, want %q", extlang.String(), got.String(), canonical.String())
+		}
+		if lang := got.PrimaryLanguage(); lang != "hak" {
+			t.Errorf("PrimaryLanguage() = %q, want %q", lang, "hak")
+		}
+	})
+}
+
+// TestParser_ToPrimaryForm_CorruptRegistry tests that ToPrimaryForm can handle
+// a malformed Preferred-Value from a corrupt registry.
+func TestParser_ToPrimaryForm_CorruptRegistry(t *testing.T) {
+	corruptParser := newTestParser(map[string]Record{
+		"language:zh": {Type: "language", Subtag: "zh"},
+		"extlang:hak": {Type: "extlang", Subtag: "hak", Prefix: []string{"zh"}, PreferredValue: "ha--k"},
+	})
+
+	lt, err := corruptParser.Parse("zh-hak")
+	if err != nil {
+		t.Fatalf("Initial Parse failed unexpectedly: %v", err)
+	}
+
+	_, err = corruptParser.ToPrimaryForm(lt)
+	if !errors.Is(err, ErrEmptySubtag) {
+		t.Errorf("ToPrimaryForm with corrupt registry did not return the expected error.\nGot: %v\nWant: %v",
+			err, ErrEmptySubtag)
+	}
+}
+
 // TestParser_ToExtlangForm_CorruptRegistry tests that ToExtlangForm can handle
 // a malformed prefix from a corrupt registry, exercising a defensive error check.
 func TestParser_ToExtlangForm_CorruptRegistry(t *testing.T) {
@@ -0,0 +1,59 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"slices"
+	"strings"
+)
+
+// Macrolanguage returns the macrolanguage encompassing the primary language
+// of a tag, as recorded in the registry (e.g., "zh" for "cmn"). The tag is
+// canonicalized first, so an extended language subtag is promoted to the
+// primary language: "zh-yue" gives the macrolanguage of "yue". It returns
+// false if the language is itself a macrolanguage or is not encompassed by
+// any.
+func (p *Parser) Macrolanguage(lt LanguageTag) (string, bool) {
+	canonical := p.canonicalOrSelf(lt)
+	macro := p.macrolanguage(strings.ToLower(canonical.PrimaryLanguage()))
+	return macro, macro != ""
+}
+
+// EncompassedLanguages returns the sorted primary language subtags of every
+// language the registry records as encompassed by the given macrolanguage
+// (e.g., "cmn" and "yue" for "zh"). It returns nil if there is none. Like
+// RecordsByType, each call scans the whole registry.
+func (p *Parser) EncompassedLanguages(macro string) []string {
+	var languages []string
+	for _, rec := range p.registry.Records {
+		if rec.Type == "language" && rec.Macrolanguage != "" && strings.EqualFold(rec.Macrolanguage, macro) {
+			languages = append(languages, rec.Subtag)
+		}
+	}
+	slices.Sort(languages)
+	return languages
+}
+
+// macrolanguage returns the lowercase macrolanguage encompassing a lowercase
+// primary language subtag, or an empty string if there is none.
+func (p *Parser) macrolanguage(lang string) string {
+	rec, ok := p.registry.Records["language:"+lang]
+	if !ok {
+		return ""
+	}
+	return strings.ToLower(rec.Macrolanguage)
+}
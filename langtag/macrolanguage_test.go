@@ -0,0 +1,80 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestParser_Macrolanguage tests the lookup of the macrolanguage of a tag.
+func TestParser_Macrolanguage(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   string
+		wantOk bool
+	}{
+		{tag: "cmn", want: "zh", wantOk: true},
+		{tag: "yue-HK", want: "zh", wantOk: true},
+		{tag: "zh-yue", want: "zh", wantOk: true},
+		{tag: "zh-cmn-Hans-CN", want: "zh", wantOk: true},
+		{tag: "arb", want: "ar", wantOk: true},
+		{tag: "zh"},
+		{tag: "en-US"},
+		{tag: "qaa"},
+		{tag: "x-private"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, ok := p.Macrolanguage(mustParse(t, tt.tag))
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("Macrolanguage() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+// TestParser_EncompassedLanguages tests the listing of the languages of a
+// macrolanguage.
+func TestParser_EncompassedLanguages(t *testing.T) {
+	zh := p.EncompassedLanguages("zh")
+	for _, lang := range []string{"cmn", "yue", "nan", "hak"} {
+		if !slices.Contains(zh, lang) {
+			t.Errorf("EncompassedLanguages(\"zh\") is missing %q", lang)
+		}
+	}
+	if !slices.IsSorted(zh) {
+		t.Error("EncompassedLanguages(\"zh\") is not sorted")
+	}
+	if got := p.EncompassedLanguages("ZH"); !slices.Equal(got, zh) {
+		t.Errorf("EncompassedLanguages(\"ZH\") = %v, want %v", got, zh)
+	}
+	if got := p.EncompassedLanguages("en"); got != nil {
+		t.Errorf("EncompassedLanguages(\"en\") = %v, want nil", got)
+	}
+
+	parser := newTestParser(map[string]Record{
+		"language:zh":  {Type: "language", Subtag: "zh", Scope: "macrolanguage"},
+		"language:yue": {Type: "language", Subtag: "yue", Macrolanguage: "zh"},
+		"language:cmn": {Type: "language", Subtag: "cmn", Macrolanguage: "zh"},
+		"extlang:yue":  {Type: "extlang", Subtag: "yue", Macrolanguage: "zh"},
+	})
+	if got, want := parser.EncompassedLanguages("zh"), []string{"cmn", "yue"}; !slices.Equal(got, want) {
+		t.Errorf("EncompassedLanguages(\"zh\") = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// wildcardRange is the language range matching any language tag, as defined
+// in RFC 4647, Section 2.1.
+const wildcardRange = "*"
+
+// Wildcard returns the language range "*", which matches any language tag
+// when used in a priority list given to Filter. It is ignored by Match.
+func Wildcard() LanguageTag {
+	end := len(wildcardRange)
+	return LanguageTag{
+		tag: wildcardRange,
+		positions: tagElementsPositions{
+			languageEnd:  end,
+			extlangEnd:   end,
+			scriptEnd:    end,
+			regionEnd:    end,
+			variantEnd:   end,
+			extensionEnd: end,
+		},
+	}
+}
+
+// IsWildcard returns true if the tag is the language range "*".
+func (lt *LanguageTag) IsWildcard() bool {
+	return lt.tag == wildcardRange
+}
+
+// Match selects the best available tag for a priority list of language ranges
+// using the "Lookup" scheme described in RFC 4647, Section 3.4.
+//
+// Each tag of the priority list is tried in order. When no available tag
+// matches it, subtags are progressively removed from the right, together with
+// any singleton left dangling at the end, until a match is found or nothing
+// remains. The comparison is case-insensitive, and both sides are first
+// canonicalized through the registry so that, for instance, "iw" matches "he".
+// The wildcard range "*" is ignored, as recommended by RFC 4647.
+//
+// It returns the matching tag as it appears in available, and true. If no tag
+// matches, it returns the zero LanguageTag and false.
+func (p *Parser) Match(priorityList []LanguageTag, available []LanguageTag) (LanguageTag, bool) {
+	index := make(map[string]int, len(available))
+	for i := range available {
+		key := p.matchingKey(available[i])
+		if _, ok := index[key]; !ok {
+			index[key] = i
+		}
+	}
+
+	for i := range priorityList {
+		candidate := p.matchingKey(priorityList[i])
+		if candidate == wildcardRange {
+			continue
+		}
+		for candidate != "" {
+			if j, ok := index[candidate]; ok {
+				return available[j], true
+			}
+			candidate = truncateRange(candidate)
+		}
+	}
+
+	return LanguageTag{}, false
+}
+
+// Filter returns every available tag matched by at least one range of the
+// priority list using the "Basic Filtering" scheme described in RFC 4647,
+// Section 3.3.1. A range matches a tag if it is equal to the tag, or if it is
+// a prefix of the tag followed by a "-". The wildcard range "*" matches every
+// tag. As in Match, the comparison is case-insensitive and done on the
+// canonical forms of both sides.
+//
+// The returned tags keep the order in which they appear in available, and
+// tags that are equivalent to an already returned one are omitted.
+//
+// Extended language ranges, such as "de-*-DE", cannot be parsed into a
+// LanguageTag and are handled by FilterExtended instead.
+func (p *Parser) Filter(priorityList []LanguageTag, available []LanguageTag) []LanguageTag {
+	ranges := make([]string, len(priorityList))
+	for i := range priorityList {
+		ranges[i] = p.matchingKey(priorityList[i])
+	}
+
+	var filtered []LanguageTag
+	seen := make(map[string]struct{}, len(available))
+	for i := range available {
+		key := p.matchingKey(available[i])
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		for _, r := range ranges {
+			if r == wildcardRange || key == r || (strings.HasPrefix(key, r) && key[len(r)] == '-') {
+				seen[key] = struct{}{}
+				filtered = append(filtered, available[i])
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// FilterExtended returns every available tag matched by at least one
+// extended language range of the priority list using the "Extended
+// Filtering" scheme described in RFC 4647, Section 3.3.2. An extended range
+// is a string such as "de-*-DE", where "*" matches any sequence of subtags,
+// and the subtags missing between those of the range are skipped, except
+// singletons: "de-*-DE", like "de-DE", matches "de-DE", "de-Latn-DE" and
+// "de-DE-x-goethe", but neither "de-Deva" nor "de-x-DE". As in Filter, the
+// comparison is case-insensitive and done on the canonical forms of the tags,
+// and of the ranges without a "*".
+//
+// The returned tags keep the order in which they appear in available, and
+// tags that are equivalent to an already returned one are omitted. An error
+// is returned if a range is not a well-formed extended language range.
+func (p *Parser) FilterExtended(priorityList []string, available []LanguageTag) ([]LanguageTag, error) {
+	ranges := make([][]string, len(priorityList))
+	for i, r := range priorityList {
+		if err := validateExtendedRange(r); err != nil {
+			return nil, fmt.Errorf("%w: %q", err, r)
+		}
+		key := strings.ToLower(r)
+		if !strings.Contains(key, wildcardRange) {
+			if lt, err := p.Parse(r); err == nil {
+				key = p.matchingKey(lt)
+			}
+		}
+		ranges[i] = strings.Split(key, "-")
+	}
+
+	var filtered []LanguageTag
+	seen := make(map[string]struct{}, len(available))
+	for i := range available {
+		key := p.matchingKey(available[i])
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		subtags := strings.Split(key, "-")
+		for _, r := range ranges {
+			if extendedRangeMatches(r, subtags) {
+				seen[key] = struct{}{}
+				filtered = append(filtered, available[i])
+				break
+			}
+		}
+	}
+
+	return filtered, nil
+}
+
+// validateExtendedRange checks that r is a well-formed extended language
+// range (RFC 4647, Section 2.2): subtags of one to eight alphanumeric
+// characters, the first one alphabetic, any of which can be "*".
+func validateExtendedRange(r string) error {
+	for i, subtag := range strings.Split(r, "-") {
+		if subtag == wildcardRange {
+			continue
+		}
+		if err := validateSubtag(subtag); err != nil {
+			return err
+		}
+		if (i == 0 && !isAlphabetic(subtag)) || !isAlphanumeric(subtag) {
+			return ErrForbiddenChar
+		}
+	}
+	return nil
+}
+
+// extendedRangeMatches applies the matching algorithm of RFC 4647,
+// Section 3.3.2 to the lowercase subtags of a range and of a tag.
+func extendedRangeMatches(rangeSubtags, tagSubtags []string) bool {
+	if rangeSubtags[0] != wildcardRange && rangeSubtags[0] != tagSubtags[0] {
+		return false
+	}
+	r, t := 1, 1
+	for r < len(rangeSubtags) {
+		switch {
+		case rangeSubtags[r] == wildcardRange:
+			r++
+		case t >= len(tagSubtags):
+			return false
+		case rangeSubtags[r] == tagSubtags[t]:
+			r++
+			t++
+		case len(tagSubtags[t]) == 1:
+			// A singleton cannot be skipped over.
+			return false
+		default:
+			t++
+		}
+	}
+	return true
+}
+
+// CanonicalEqual reports whether two tags are equivalent once canonicalized
+// through the registry, ignoring case. For instance, "iw" equals "he" because
+// "iw" is deprecated in favor of "he", and "zh-cmn" equals "cmn" because of its
+// extlang. Tags that cannot be canonicalized are compared as they are.
+func (p *Parser) CanonicalEqual(a, b LanguageTag) bool {
+	return p.matchingKey(a) == p.matchingKey(b)
+}
+
+// CanonicalKey returns a stable, lowercase string identifying the tag, which
+// can be used as a map key since LanguageTag itself cannot. The key is the
+// canonical form computed with the shared parser returned by Default, so two
+// tags have the same key if and only if they are equivalent according to
+// CanonicalEqual, e.g. "IW-il" and "he-IL". If the default parser cannot be
+// created, the key is the lowercase tag.
+func (lt *LanguageTag) CanonicalKey() string {
+	p, err := Default()
+	if err != nil {
+		return strings.ToLower(lt.tag)
+	}
+	return p.matchingKey(*lt)
+}
+
+// matchingKey returns the lowercase canonical form of a tag used to compare it
+// with other tags. Tags that cannot be canonicalized are compared as they are.
+func (p *Parser) matchingKey(lt LanguageTag) string {
+	if lt.IsWildcard() {
+		return wildcardRange
+	}
+	canonical := p.canonicalOrSelf(lt)
+	return strings.ToLower(canonical.String())
+}
+
+// canonicalOrSelf returns the canonical form of a tag, or the tag itself if it
+// cannot be canonicalized (e.g., because it contains unregistered subtags).
+func (p *Parser) canonicalOrSelf(lt LanguageTag) LanguageTag {
+	if canonical, err := p.ParseAndNormalize(lt.String()); err == nil {
+		return canonical
+	}
+	return lt
+}
+
+// truncateRange removes the last subtag of a language range. If the subtag
+// that then ends the range is a singleton, it is removed as well, as per
+// RFC 4647, Section 3.4.
+func truncateRange(r string) string {
+	i := strings.LastIndexByte(r, '-')
+	if i < 0 {
+		return ""
+	}
+	r = r[:i]
+	if i = strings.LastIndexByte(r, '-'); i >= 0 && len(r)-i-1 == 1 {
+		r = r[:i]
+	}
+	return r
+}
@@ -0,0 +1,425 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//nolint:testpackage // This is a white-box test file for an internal package. It needs to be in the same package to test unexported functions.
+package langtag
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// parseAll is a test helper that parses every given tag with p.Parse.
+func parseAll(t *testing.T, tags ...string) []LanguageTag {
+	t.Helper()
+	lts := make([]LanguageTag, 0, len(tags))
+	for _, tag := range tags {
+		lts = append(lts, mustParse(t, tag))
+	}
+	return lts
+}
+
+// TestTruncateRange tests the removal of trailing subtags described in
+// RFC 4647, Section 3.4, including the removal of dangling singletons.
+func TestTruncateRange(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "zh-hant-cn-x-private1-private2", want: "zh-hant-cn-x-private1"},
+		{in: "zh-hant-cn-x-private1", want: "zh-hant-cn"},
+		{in: "zh-hant-cn", want: "zh-hant"},
+		{in: "zh-hant", want: "zh"},
+		{in: "zh", want: ""},
+		{in: "en-a-bbb-x", want: "en-a-bbb"},
+		{in: "x-foo", want: "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := truncateRange(tt.in); got != tt.want {
+				t.Errorf("truncateRange(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParser_Match tests the "Lookup" scheme of RFC 4647, Section 3.4.
+func TestParser_Match(t *testing.T) {
+	tests := []struct {
+		name      string
+		priority  []string
+		available []string
+		want      string
+		wantOk    bool
+	}{
+		{
+			name:      "Exact match",
+			priority:  []string{"fr-FR"},
+			available: []string{"en", "fr-FR"},
+			want:      "fr-FR",
+			wantOk:    true,
+		},
+		{
+			name:      "Progressive truncation",
+			priority:  []string{"zh-Hant-CN-x-private1-private2"},
+			available: []string{"zh", "zh-Hant"},
+			want:      "zh-Hant",
+			wantOk:    true,
+		},
+		{
+			name:      "Dangling singleton is skipped",
+			priority:  []string{"en-a-bbb-x-a-ccc"},
+			available: []string{"en-a-bbb", "en"},
+			want:      "en-a-bbb",
+			wantOk:    true,
+		},
+		{
+			name:      "Priority order wins over specificity",
+			priority:  []string{"de-CH", "fr-FR"},
+			available: []string{"fr-FR", "de"},
+			want:      "de",
+			wantOk:    true,
+		},
+		{
+			name:      "Case-insensitive comparison",
+			priority:  []string{"EN-us"},
+			available: []string{"en-US"},
+			want:      "en-US",
+			wantOk:    true,
+		},
+		{
+			name:      "Canonical equivalence",
+			priority:  []string{"iw-IL"},
+			available: []string{"he"},
+			want:      "he",
+			wantOk:    true,
+		},
+		{
+			name:      "Canonical equivalence on the available side",
+			priority:  []string{"he"},
+			available: []string{"iw"},
+			want:      "iw",
+			wantOk:    true,
+		},
+		{
+			name:      "Returns the first equivalent available tag",
+			priority:  []string{"he"},
+			available: []string{"iw", "he"},
+			want:      "iw",
+			wantOk:    true,
+		},
+		{
+			name:      "Tag not in the registry is compared as is",
+			priority:  []string{"zz-Zzzz"},
+			available: []string{"ZZ"},
+			want:      "zz",
+			wantOk:    true,
+		},
+		{
+			name:      "No match",
+			priority:  []string{"ja-JP"},
+			available: []string{"en", "fr"},
+		},
+		{
+			name:      "Empty priority list",
+			available: []string{"en"},
+		},
+		{
+			name:     "Empty available list",
+			priority: []string{"en"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := p.Match(parseAll(t, tt.priority...), parseAll(t, tt.available...))
+			if ok != tt.wantOk {
+				t.Fatalf("Match() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Match() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestParser_Match_Wildcard tests that the wildcard range is ignored by the
+// "Lookup" scheme, as recommended by RFC 4647, Section 3.4.
+func TestParser_Match_Wildcard(t *testing.T) {
+	priority := []LanguageTag{Wildcard(), mustParse(t, "fr")}
+	available := parseAll(t, "en", "fr")
+
+	got, ok := p.Match(priority, available)
+	if !ok || got.String() != "fr" {
+		t.Errorf("Match() = (%q, %v), want (%q, true)", got.String(), ok, "fr")
+	}
+}
+
+// TestWildcard tests the language range "*".
+func TestWildcard(t *testing.T) {
+	w := Wildcard()
+	if !w.IsWildcard() {
+		t.Error("Wildcard().IsWildcard() = false, want true")
+	}
+	if got := w.PrimaryLanguage(); got != "*" {
+		t.Errorf("Wildcard().PrimaryLanguage() = %q, want %q", got, "*")
+	}
+	if _, ok := w.Region(); ok {
+		t.Error("Wildcard().Region() reported a region")
+	}
+	lt := mustParse(t, "en")
+	if lt.IsWildcard() {
+		t.Error("IsWildcard() = true for \"en\", want false")
+	}
+}
+
+// TestParser_CanonicalEqual tests the comparison of tags after
+// canonicalization.
+func TestParser_CanonicalEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "Different case", a: "EN-us", b: "en-US", want: true},
+		{name: "Deprecated language", a: "iw", b: "he", want: true},
+		{name: "Deprecated language with region", a: "iw-IL", b: "he-il", want: true},
+		{name: "Deprecated region", a: "en-BU", b: "en-MM", want: true},
+		{name: "Extlang", a: "zh-cmn", b: "cmn", want: true},
+		{name: "Extlang with script", a: "zh-yue-Hant", b: "yue-Hant", want: true},
+		{name: "Grandfathered", a: "art-lojban", b: "jbo", want: true},
+		{name: "Different languages", a: "he", b: "yi", want: false},
+		{name: "Macrolanguage is not its member", a: "zh", b: "cmn", want: false},
+		{name: "Different regions", a: "en-US", b: "en-GB", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.CanonicalEqual(mustParse(t, tt.a), mustParse(t, tt.b)); got != tt.want {
+				t.Errorf("CanonicalEqual(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLanguageTag_CanonicalKey tests that equivalent tags share the same key,
+// whatever their case, and that different tags do not.
+func TestLanguageTag_CanonicalKey(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want string
+	}{
+		{name: "Different case", tags: []string{"en-US", "EN-us", "en-us"}, want: "en-us"},
+		{name: "Script case", tags: []string{"sr-Latn-RS", "SR-LATN-rs"}, want: "sr-latn-rs"},
+		{name: "Deprecated language", tags: []string{"he-IL", "IW-il"}, want: "he-il"},
+		{name: "Extlang", tags: []string{"cmn", "ZH-CMN"}, want: "cmn"},
+		{name: "Private use", tags: []string{"x-Whatever", "X-WHATEVER"}, want: "x-whatever"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, tag := range tt.tags {
+				lt := mustParse(t, tag)
+				if got := lt.CanonicalKey(); got != tt.want {
+					t.Errorf("CanonicalKey() of %q = %q, want %q", tag, got, tt.want)
+				}
+			}
+		})
+	}
+
+	en, fr := mustParse(t, "en"), mustParse(t, "fr")
+	if en.CanonicalKey() == fr.CanonicalKey() {
+		t.Error("different tags have the same CanonicalKey()")
+	}
+}
+
+// TestParser_Filter tests the "Basic Filtering" scheme of RFC 4647,
+// Section 3.3.1.
+func TestParser_Filter(t *testing.T) {
+	tests := []struct {
+		name      string
+		priority  []LanguageTag
+		available []string
+		want      []string
+	}{
+		{
+			name:      "RFC 4647 de-de range",
+			priority:  parseAll(t, "de-de"),
+			available: []string{"de", "de-DE", "de-DE-1996", "de-Deva", "de-Latn-DE", "de-CH"},
+			want:      []string{"de-DE", "de-DE-1996"},
+		},
+		{
+			name:      "RFC 4647 en range",
+			priority:  parseAll(t, "en"),
+			available: []string{"en", "en-US", "en-GB", "eng", "enm", "fr"},
+			want:      []string{"en", "en-US", "en-GB"},
+		},
+		{
+			name:      "Several ranges keep the available order",
+			priority:  parseAll(t, "fr", "en"),
+			available: []string{"en-US", "de", "fr-CA", "fr"},
+			want:      []string{"en-US", "fr-CA", "fr"},
+		},
+		{
+			name:      "Duplicates are removed",
+			priority:  parseAll(t, "en", "en-US"),
+			available: []string{"en-US", "EN-us", "en"},
+			want:      []string{"en-US", "en"},
+		},
+		{
+			name:      "Canonical equivalence",
+			priority:  parseAll(t, "iw"),
+			available: []string{"he-IL", "iw", "ar"},
+			want:      []string{"he-IL", "iw"},
+		},
+		{
+			name:      "Wildcard matches everything",
+			priority:  []LanguageTag{Wildcard()},
+			available: []string{"de", "x-private", "i-klingon"},
+			want:      []string{"de", "x-private", "i-klingon"},
+		},
+		{
+			name:      "No match",
+			priority:  parseAll(t, "ja"),
+			available: []string{"en", "fr"},
+		},
+		{
+			name:      "Empty priority list",
+			available: []string{"en"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Filter(tt.priority, parseAll(t, tt.available...))
+			gotTags := make([]string, 0, len(got))
+			for i := range got {
+				gotTags = append(gotTags, got[i].String())
+			}
+			if len(gotTags) != len(tt.want) {
+				t.Fatalf("Filter() = %q, want %q", gotTags, tt.want)
+			}
+			for i := range gotTags {
+				if gotTags[i] != tt.want[i] {
+					t.Errorf("Filter() = %q, want %q", gotTags, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+}
+
+// TestParser_FilterExtended tests extended filtering with the examples of
+// RFC 4647, Section 3.3.2.
+func TestParser_FilterExtended(t *testing.T) {
+	// "de-DE" and "de-de" are left out, since they are equivalent to
+	// "de-Latn-DE", Latn being the suppressed script of German, and would
+	// then hide it.
+	rfcTags := []string{
+		"de", "de-Latn-DE", "de-Latf-DE", "de-DE-x-goethe", "de-Latn-DE-1996", "de-Deva-DE", "de-Deva", "de-x-DE",
+	}
+	rfcMatches := []string{"de-Latn-DE", "de-Latf-DE", "de-DE-x-goethe", "de-Latn-DE-1996", "de-Deva-DE"}
+
+	tests := []struct {
+		name      string
+		priority  []string
+		available []string
+		want      []string
+	}{
+		{name: "RFC 4647 de-*-DE range", priority: []string{"de-*-DE"}, available: rfcTags, want: rfcMatches},
+		{name: "RFC 4647 de-DE range", priority: []string{"de-DE"}, available: rfcTags, want: rfcMatches},
+		{name: "Case-insensitive range", priority: []string{"DE-*-de"}, available: rfcTags, want: rfcMatches},
+		{
+			name:      "Wildcard primary language",
+			priority:  []string{"*-CH"},
+			available: []string{"de-CH", "fr-Latn-CH", "it", "de"},
+			want:      []string{"de-CH", "fr-Latn-CH"},
+		},
+		{
+			name:      "Wildcard range matches everything",
+			priority:  []string{"*"},
+			available: []string{"de", "x-private", "i-klingon"},
+			want:      []string{"de", "x-private", "i-klingon"},
+		},
+		{
+			name:      "Range as a basic one",
+			priority:  []string{"en"},
+			available: []string{"en", "en-US", "en-GB", "eng", "fr"},
+			want:      []string{"en", "en-US", "en-GB"},
+		},
+		{
+			name:      "Trailing subtags are skipped",
+			priority:  []string{"zh-*-TW"},
+			available: []string{"zh-Hant-TW", "zh-TW", "zh-Hant", "zh-Hant-HK"},
+			want:      []string{"zh-Hant-TW", "zh-TW"},
+		},
+		{
+			name:      "Canonical equivalence without a wildcard",
+			priority:  []string{"iw"},
+			available: []string{"he-IL", "iw", "ar"},
+			want:      []string{"he-IL", "iw"},
+		},
+		{
+			name:      "Several ranges keep the available order and remove duplicates",
+			priority:  []string{"fr-*-CA", "de-DE"},
+			available: []string{"de-Latf-DE", "fr-CA", "de-de", "fr-Cyrl-CA", "de-DE", "de-Latn-DE"},
+			want:      []string{"de-Latf-DE", "fr-CA", "de-DE", "fr-Cyrl-CA"},
+		},
+		{name: "Empty priority list", available: []string{"en"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.FilterExtended(tt.priority, parseAll(t, tt.available...))
+			if err != nil {
+				t.Fatalf("FilterExtended() unexpected error: %v", err)
+			}
+			gotTags := make([]string, 0, len(got))
+			for i := range got {
+				gotTags = append(gotTags, got[i].String())
+			}
+			if !slices.Equal(gotTags, tt.want) {
+				t.Errorf("FilterExtended() = %q, want %q", gotTags, tt.want)
+			}
+		})
+	}
+
+	t.Run("Basic filtering does not skip subtags", func(t *testing.T) {
+		if got := p.Filter(parseAll(t, "de-DE"), parseAll(t, "de-Latf-DE")); len(got) != 0 {
+			t.Errorf("Filter() = %v, want no tag", got)
+		}
+	})
+
+	t.Run("Malformed ranges", func(t *testing.T) {
+		for r, wantErr := range map[string]error{
+			"":           ErrEmptySubtag,
+			"de--DE":     ErrEmptySubtag,
+			"de-*-":      ErrEmptySubtag,
+			"toolongtag": ErrSubtagTooLong,
+			"1de":        ErrForbiddenChar,
+			"de_DE":      ErrForbiddenChar,
+			"de-**":      ErrForbiddenChar,
+		} {
+			if _, err := p.FilterExtended([]string{"en", r}, parseAll(t, "en")); !errors.Is(err, wantErr) {
+				t.Errorf("FilterExtended(%q) error = %v, want %v", r, err, wantErr)
+			}
+		}
+	})
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidPOSIXLocale is returned when a POSIX locale identifier cannot be
+// converted to a language tag.
+var ErrInvalidPOSIXLocale = errors.New("the POSIX locale identifier cannot be converted to a language tag")
+
+// posixModifier describes the subtag that a POSIX locale "@modifier" stands
+// for. A modifier with neither a script nor a variant carries no information
+// that a language tag can express, and is dropped.
+type posixModifier struct {
+	script  string
+	variant string
+}
+
+// posixModifiers maps the lowercase POSIX locale modifiers known to FromPOSIX
+// to the subtags they stand for.
+//
+//nolint:gochecknoglobals // Static lookup table.
+var posixModifiers = map[string]posixModifier{
+	"cyrillic":   {script: "Cyrl"},
+	"devanagari": {script: "Deva"},
+	"euro":       {},
+	"iqtelif":    {script: "Latn"},
+	"latin":      {script: "Latn"},
+	"valencia":   {variant: "valencia"},
+}
+
+// posixScriptModifiers maps scripts to the POSIX locale modifier written by
+// ToPOSIX. It is not the exact inverse of posixModifiers, since several
+// modifiers can stand for the same script.
+//
+//nolint:gochecknoglobals // Static lookup table.
+var posixScriptModifiers = map[string]string{
+	"Cyrl": "cyrillic",
+	"Deva": "devanagari",
+	"Latn": "latin",
+}
+
+// FromPOSIX converts a POSIX locale identifier of the form
+// "language[_territory][.codeset][@modifier]" to a valid language tag in
+// canonical form, e.g. "sr_RS.UTF-8@latin" gives "sr-Latn-RS".
+//
+// The codeset is discarded, since language tags do not describe character
+// encodings. The modifiers "latin", "cyrillic", "devanagari" and "iqtelif"
+// become script subtags, "valencia" becomes a variant subtag, and "euro" is
+// discarded. Any other modifier is rejected with ErrInvalidPOSIXLocale, as it
+// might change the meaning of the locale. The "C" and "POSIX" locales give
+// "und".
+//
+// Errors wrap ErrInvalidPOSIXLocale, together with the underlying parsing or
+// validation error, if any.
+func (p *Parser) FromPOSIX(locale string) (LanguageTag, error) {
+	if locale == "C" || locale == "POSIX" {
+		return p.ParseAndNormalize(rootLanguage)
+	}
+
+	base, modifier, hasModifier := strings.Cut(locale, "@")
+	base, _, _ = strings.Cut(base, ".")
+	lt, err := p.ParseLenient(base)
+	if err != nil {
+		return LanguageTag{}, fmt.Errorf("%w: %w", ErrInvalidPOSIXLocale, err)
+	}
+
+	if hasModifier {
+		mod, ok := posixModifiers[strings.ToLower(modifier)]
+		if !ok {
+			return LanguageTag{}, fmt.Errorf("%w: unknown modifier %q", ErrInvalidPOSIXLocale, modifier)
+		}
+		if mod.script != "" {
+			if lt, err = lt.WithScript(mod.script); err != nil {
+				return LanguageTag{}, fmt.Errorf("%w: %w", ErrInvalidPOSIXLocale, err)
+			}
+		}
+		if mod.variant != "" {
+			pos := lt.positions
+			if lt, err = rebuildTag(lt.tag[:pos.regionEnd], mod.variant, lt.tag[pos.regionEnd:]); err != nil {
+				return LanguageTag{}, fmt.Errorf("%w: %w", ErrInvalidPOSIXLocale, err)
+			}
+		}
+	}
+
+	if lt, err = p.ParseAndNormalize(lt.String()); err != nil {
+		return LanguageTag{}, fmt.Errorf("%w: %w", ErrInvalidPOSIXLocale, err)
+	}
+	return lt, nil
+}
+
+// ToPOSIX converts the tag to a POSIX locale identifier of the form
+// "language[_territory][@modifier]", e.g. "sr-Latn-RS" gives "sr_RS@latin".
+//
+// The conversion is lossy, and FromPOSIX does not always give back the
+// original tag. An extended language subtag replaces the primary language, as
+// in "yue_HK" for "zh-yue-HK". The script is written as a modifier when one is
+// known for it, and dropped otherwise, as in "zh_TW" for "zh-Hant-TW". The
+// "valencia" variant is written as a modifier when there is no script
+// modifier, and other variants, extensions and private use subtags are
+// dropped. No codeset is written. An empty string is returned for tags without
+// a primary language subtag, such as "x-whatever" or "i-klingon".
+func (lt *LanguageTag) ToPOSIX() string {
+	if lt.positions.languageEnd < minLanguageLen {
+		return ""
+	}
+
+	language := lt.PrimaryLanguage()
+	if extlang, ok := lt.ExtendedLanguage(); ok {
+		language = extlang
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.ToLower(language))
+	if region, ok := lt.Region(); ok {
+		b.WriteByte('_')
+		b.WriteString(strings.ToUpper(region))
+	}
+
+	modifier := ""
+	if script, ok := lt.Script(); ok {
+		modifier = posixScriptModifiers[script]
+	}
+	isValencia := func(v string) bool { return strings.EqualFold(v, "valencia") }
+	if modifier == "" && slices.ContainsFunc(lt.VariantSubtags(), isValencia) {
+		modifier = "valencia"
+	}
+	if modifier != "" {
+		b.WriteByte('@')
+		b.WriteString(modifier)
+	}
+	return b.String()
+}
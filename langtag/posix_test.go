@@ -0,0 +1,115 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParser_FromPOSIX tests the conversion of POSIX locale identifiers to
+// language tags.
+func TestParser_FromPOSIX(t *testing.T) {
+	tests := []struct {
+		name    string
+		locale  string
+		want    string
+		wantErr error
+	}{
+		{name: "Language only", locale: "fr", want: "fr"},
+		{name: "Language and territory", locale: "en_US", want: "en-US"},
+		{name: "Codeset", locale: "en_US.UTF-8", want: "en-US"},
+		{name: "Latin modifier", locale: "sr_RS@latin", want: "sr-Latn-RS"},
+		{name: "Codeset and modifier", locale: "sr_RS.UTF-8@latin", want: "sr-Latn-RS"},
+		{name: "Cyrillic modifier", locale: "uz_UZ@cyrillic", want: "uz-Cyrl-UZ"},
+		{name: "Suppressed script", locale: "de_DE@latin", want: "de-DE"},
+		{name: "Variant modifier", locale: "ca_ES.UTF-8@valencia", want: "ca-ES-valencia"},
+		{name: "Dropped modifier", locale: "de_DE@euro", want: "de-DE"},
+		{name: "Deprecated language", locale: "iw_IL.ISO-8859-8", want: "he-IL"},
+		{name: "C locale", locale: "C", want: "und"},
+		{name: "POSIX locale", locale: "POSIX", want: "und"},
+		{name: "Unknown modifier", locale: "aa_ER@saaho", wantErr: ErrInvalidPOSIXLocale},
+		{name: "Empty locale", locale: "", wantErr: ErrInvalidPOSIXLocale},
+		{name: "Codeset only", locale: ".UTF-8", wantErr: ErrInvalidPOSIXLocale},
+		{name: "Forbidden character", locale: "en US", wantErr: ErrForbiddenChar},
+		{name: "Unregistered language", locale: "zz_US", wantErr: ErrInvalidLanguage},
+		{name: "Wrapped error", locale: "zz_US", wantErr: ErrInvalidPOSIXLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.FromPOSIX(tt.locale)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FromPOSIX(%q) error = %v, wantErr %v", tt.locale, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("FromPOSIX(%q) = %q, want %q", tt.locale, got.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestLanguageTag_ToPOSIX tests the lossy conversion of language tags to POSIX
+// locale identifiers.
+func TestLanguageTag_ToPOSIX(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{tag: "fr", want: "fr"},
+		{tag: "en-US", want: "en_US"},
+		{tag: "sr-Latn-RS", want: "sr_RS@latin"},
+		{tag: "uz-Cyrl", want: "uz@cyrillic"},
+		{tag: "zh-Hant-TW", want: "zh_TW"},
+		{tag: "zh-yue-HK", want: "yue_HK"},
+		{tag: "ca-ES-valencia", want: "ca_ES@valencia"},
+		{tag: "de-CH-1996", want: "de_CH"},
+		{tag: "en-US-u-ca-gregory-x-foo", want: "en_US"},
+		{tag: "es-419", want: "es_419"},
+		{tag: "x-whatever", want: ""},
+		{tag: "i-klingon", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			lt := mustParse(t, tt.tag)
+			if got := lt.ToPOSIX(); got != tt.want {
+				t.Errorf("ToPOSIX() of %q = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+
+	var zero LanguageTag
+	if got := zero.ToPOSIX(); got != "" {
+		t.Errorf("ToPOSIX() of the zero LanguageTag = %q, want an empty string", got)
+	}
+}
+
+// TestPOSIX_RoundTrip tests that tags that POSIX can express survive a round
+// trip through ToPOSIX and FromPOSIX.
+func TestPOSIX_RoundTrip(t *testing.T) {
+	for _, tag := range []string{"sr-Latn-RS", "en-US", "ca-ES-valencia", "uz-Cyrl-UZ"} {
+		lt := mustParse(t, tag)
+		got, err := p.FromPOSIX(lt.ToPOSIX())
+		if err != nil {
+			t.Fatalf("FromPOSIX(%q) returned an unexpected error: %v", lt.ToPOSIX(), err)
+		}
+		if got.String() != tag {
+			t.Errorf("round trip of %q through %q gave %q", tag, lt.ToPOSIX(), got.String())
+		}
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	_ "embed" // Note the blank import for go:embed
+	"strings"
+	"sync"
+)
+
+// embeddedRegionData holds the UN M.49 area containment table. Each line of
+// the file holds a numeric M.49 code, the ISO 3166-1 alpha-2 code of the
+// country or "-" for a geographical region, and the numeric code of the
+// containing region or "-". Empty lines and lines starting with '#' are
+// ignored. The source of the data is documented at the top of the file.
+//
+//go:embed un-m49-regions
+var embeddedRegionData string
+
+// regionTable is the parsed UN M.49 containment table.
+type regionTable struct {
+	// parents maps a numeric code to the numeric code of its containing region.
+	parents map[string]string
+	// numeric maps an alpha-2 country code to its numeric code.
+	numeric map[string]string
+}
+
+// regions lazily parses the embedded UN M.49 containment table.
+//
+//nolint:gochecknoglobals // The static containment table is parsed once and shared by the whole package.
+var regions = sync.OnceValue(func() *regionTable {
+	return parseRegionTable(embeddedRegionData)
+})
+
+// parseRegionTable parses a UN M.49 containment table in the format of the
+// embedded un-m49-regions file. Malformed lines are ignored.
+func parseRegionTable(data string) *regionTable {
+	table := &regionTable{
+		parents: make(map[string]string),
+		numeric: make(map[string]string),
+	}
+	for line := range strings.Lines(data) {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		code, alpha2, parent := fields[0], fields[1], fields[2]
+		if alpha2 != "-" {
+			table.numeric[alpha2] = code
+		}
+		if parent != "-" {
+			table.parents[code] = parent
+		}
+	}
+	return table
+}
+
+// RegionContains reports whether the region container contains the region
+// region, according to the UN M.49 standard. Both may be given as ISO 3166-1
+// alpha-2 country codes, in any case, or as numeric M.49 codes, so that
+// RegionContains("150", "FR") and RegionContains("150", "250") both report
+// that France is in Europe. Deprecated region subtags are first replaced by
+// their preferred value in the registry.
+//
+// Containment is strict: a region does not contain itself. Unknown codes are
+// not contained in any region.
+func (p *Parser) RegionContains(container, region string) bool {
+	table := regions()
+	container = p.numericRegion(table, container)
+	for code := table.parents[p.numericRegion(table, region)]; code != ""; code = table.parents[code] {
+		if code == container {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainingRegions returns the numeric M.49 codes of all the regions
+// containing the given region, from the smallest to the World ("001"). As in
+// RegionContains, the region may be an alpha-2 or a numeric code. For instance,
+// the containing regions of "FR" are "155" (Western Europe), "150" (Europe)
+// and "001". It returns nil for the World itself and for unknown codes.
+func (p *Parser) ContainingRegions(region string) []string {
+	table := regions()
+	var containers []string
+	for code := table.parents[p.numericRegion(table, region)]; code != ""; code = table.parents[code] {
+		containers = append(containers, code)
+	}
+	return containers
+}
+
+// numericRegion returns the numeric M.49 code of a region given as an alpha-2
+// or a numeric code. Alpha-2 codes are matched case-insensitively, after
+// replacing deprecated ones by their preferred value. Codes that are not in
+// the table are returned unchanged.
+func (p *Parser) numericRegion(table *regionTable, region string) string {
+	if len(region) != regionAlphaLen || !isAlphabetic(region) {
+		return region
+	}
+	region = strings.ToUpper(region)
+	if rec, ok := p.registry.Records["region:"+strings.ToLower(region)]; ok && rec.PreferredValue != "" {
+		region = strings.ToUpper(rec.PreferredValue)
+	}
+	if code, ok := table.numeric[region]; ok {
+		return code
+	}
+	return region
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestParser_RegionContains tests the UN M.49 containment queries, with both
+// alpha-2 and numeric codes.
+func TestParser_RegionContains(t *testing.T) {
+	tests := []struct {
+		name      string
+		container string
+		region    string
+		want      bool
+	}{
+		{name: "Country in continent", container: "150", region: "FR", want: true},
+		{name: "Country in subregion", container: "155", region: "FR", want: true},
+		{name: "Country in World", container: "001", region: "FR", want: true},
+		{name: "Continent in World", container: "001", region: "150", want: true},
+		{name: "Lowercase alpha-2", container: "150", region: "fr", want: true},
+		{name: "Numeric country code", container: "150", region: "250", want: true},
+		{name: "Intermediate region", container: "419", region: "BR", want: true},
+		{name: "Sub-Saharan Africa", container: "202", region: "NG", want: true},
+		{name: "Channel Islands", container: "154", region: "JE", want: true},
+		{name: "Deprecated region subtag", container: "035", region: "BU", want: true},
+		{name: "Other continent", container: "142", region: "FR", want: false},
+		{name: "Reverse containment", container: "FR", region: "150", want: false},
+		{name: "Not containing itself", container: "150", region: "150", want: false},
+		{name: "Unknown region", container: "001", region: "ZZ", want: false},
+		{name: "Empty region", container: "001", region: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.RegionContains(tt.container, tt.region); got != tt.want {
+				t.Errorf("RegionContains(%q, %q) = %v, want %v", tt.container, tt.region, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParser_ContainingRegions tests the listing of the regions containing a
+// region, from the smallest to the World.
+func TestParser_ContainingRegions(t *testing.T) {
+	tests := []struct {
+		region string
+		want   []string
+	}{
+		{region: "FR", want: []string{"155", "150", "001"}},
+		{region: "250", want: []string{"155", "150", "001"}},
+		{region: "br", want: []string{"005", "419", "019", "001"}},
+		{region: "150", want: []string{"001"}},
+		{region: "AQ", want: []string{"001"}},
+		{region: "001", want: nil},
+		{region: "ZZ", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			if got := p.ContainingRegions(tt.region); !slices.Equal(got, tt.want) {
+				t.Errorf("ContainingRegions(%q) = %v, want %v", tt.region, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseRegionTable verifies that every area of the embedded UN M.49 table
+// is eventually contained in the World, and tests the parsing of a custom
+// table.
+func TestParseRegionTable(t *testing.T) {
+	table := parseRegionTable(embeddedRegionData)
+	if len(table.numeric) < 240 {
+		t.Fatalf("the table holds %d countries, want at least 240", len(table.numeric))
+	}
+	for code := range table.parents {
+		seen := 0
+		for c := code; c != "001"; c = table.parents[c] {
+			if c == "" || seen > len(table.parents) {
+				t.Fatalf("area %q is not contained in the World", code)
+			}
+			seen++
+		}
+	}
+	for _, alpha2 := range []string{"FR", "US", "JP", "TW", "GG"} {
+		if _, ok := table.numeric[alpha2]; !ok {
+			t.Errorf("the table has no numeric code for %q", alpha2)
+		}
+	}
+
+	custom := parseRegionTable("# comment\n\n001 - -\n150 - 001\nmalformed line\n250 FR 150\n")
+	if got := custom.parents["250"]; got != "150" {
+		t.Errorf("parents[%q] = %q, want %q", "250", got, "150")
+	}
+	if got := custom.numeric["FR"]; got != "250" {
+		t.Errorf("numeric[%q] = %q, want %q", "FR", got, "250")
+	}
+	if len(custom.parents) != 2 {
+		t.Errorf("the custom table holds %d containments, want 2", len(custom.parents))
+	}
+}
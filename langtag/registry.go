@@ -16,6 +16,24 @@ limitations under the License.
 
 package langtag
 
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrInconsistentRegistry is wrapped by the errors returned by
+// Registry.Validate, for records referencing a subtag the registry does not
+// define.
+var ErrInconsistentRegistry = errors.New("a registry record references an unknown subtag")
+
+// ErrSharedParser is returned when modifying the parser returned by Default,
+// which is shared by the whole process.
+var ErrSharedParser = errors.New("the default parser is shared and cannot be modified")
+
 // Registry holds the parsed data from the IANA Language Subtag Registry file.
 // It serves as the database for validating and canonicalizing language tags.
 type Registry struct {
@@ -40,7 +58,246 @@ type Record struct {
 	Comments       []string `json:"comments,omitempty"`
 }
 
+// Validate cross-checks the references between the records of the registry
+// and returns every problem found, in a stable order, or nil if there is none.
+// It is meant to catch mistakes in a custom or patched registry when it is
+// loaded, rather than when a tag using it fails to parse. The checked
+// references are:
+//   - the Prefix of extlang and variant records, whose subtags must all be
+//     defined;
+//   - the Preferred-Value of every record, which must be a defined subtag of
+//     the same type, or a tag whose subtags are all defined for grandfathered
+//     and redundant records;
+//   - the Macrolanguage and Suppress-Script fields, which must be a defined
+//     language and script, respectively.
+//
+// Each error wraps ErrInconsistentRegistry.
+func (r *Registry) Validate() []error {
+	keys := slices.Sorted(maps.Keys(r.Records))
+
+	var errs []error
+	report := func(rec Record, field, value string) {
+		name := cmp.Or(rec.Subtag, rec.Tag)
+		errs = append(errs, fmt.Errorf(
+			"%w: the %s record %q has %s %q", ErrInconsistentRegistry, rec.Type, name, field, value,
+		))
+	}
+	for _, key := range keys {
+		rec := r.Records[key]
+		for _, prefix := range rec.Prefix {
+			if !r.hasTag(prefix) {
+				report(rec, "the prefix", prefix)
+			}
+		}
+		if rec.PreferredValue != "" && !r.hasPreferredValue(rec) {
+			report(rec, "the preferred value", rec.PreferredValue)
+		}
+		if rec.Macrolanguage != "" && !r.hasSubtag("language", rec.Macrolanguage) {
+			report(rec, "the macrolanguage", rec.Macrolanguage)
+		}
+		if rec.SuppressScript != "" && !r.hasSubtag("script", rec.SuppressScript) {
+			report(rec, "the suppressed script", rec.SuppressScript)
+		}
+	}
+	return errs
+}
+
+// hasPreferredValue reports whether the Preferred-Value of rec is defined. An
+// extlang is replaced by a language with the same subtag.
+func (r *Registry) hasPreferredValue(rec Record) bool {
+	switch rec.Type {
+	case "grandfathered", "redundant":
+		return r.hasTag(rec.PreferredValue)
+	case "extlang":
+		return r.hasSubtag("language", rec.PreferredValue)
+	default:
+		return r.hasSubtag(rec.Type, rec.PreferredValue)
+	}
+}
+
+// hasTag reports whether every subtag of tag is defined: the first one as a
+// language, and the others as an extlang, script, region or variant.
+func (r *Registry) hasTag(tag string) bool {
+	subtags := strings.Split(tag, "-")
+	if !r.hasSubtag("language", subtags[0]) {
+		return false
+	}
+	for _, subtag := range subtags[1:] {
+		if !r.hasSubtag("extlang", subtag) && !r.hasSubtag("script", subtag) &&
+			!r.hasSubtag("region", subtag) && !r.hasSubtag("variant", subtag) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasSubtag reports whether the registry has a record of the given type for
+// subtag.
+func (r *Registry) hasSubtag(subtagType, subtag string) bool {
+	_, ok := r.Records[subtagType+":"+strings.ToLower(subtag)]
+	return ok
+}
+
 // IsGrandfathered returns true if the record type is 'grandfathered' or 'redundant'.
 func (r *Record) IsGrandfathered() bool {
 	return r.Type == "grandfathered" || r.Type == "redundant"
 }
+
+// RegistryDate returns the File-Date of the registry used by the parser, which
+// identifies the snapshot of the IANA registry it validates tags against. The
+// date has the "YYYY-MM-DD" format of RFC 5646, Section 3.1.1, and an empty
+// string means that the registry file had no File-Date field.
+func (p *Parser) RegistryDate() string {
+	return p.registry.FileDate
+}
+
+// SuppressScript returns the Suppress-Script field of the registry record of a
+// primary language subtag, matched case-insensitively (e.g., "Latn" for "en").
+// This is the script that should not be written in tags of that language, and
+// that canonicalization removes. It returns false if the language is unknown
+// or has no such field, as for "sr", which is commonly written in several
+// scripts.
+//
+// The registry only records a Suppress-Script for languages overwhelmingly
+// written in one script. It is not a likely script as computed by the Unicode
+// CLDR likely subtags data, which gives a script for nearly every language,
+// and, for instance, "Cyrl" for "sr".
+func (p *Parser) SuppressScript(language string) (string, bool) {
+	rec, ok := p.registry.Records["language:"+strings.ToLower(language)]
+	if !ok || rec.SuppressScript == "" {
+		return "", false
+	}
+	return rec.SuppressScript, true
+}
+
+// RecordsByType returns every registry record of the given type ("language",
+// "extlang", "script", "region", "variant", "grandfathered" or "redundant"),
+// sorted by Subtag, or by Tag for grandfathered and redundant records. The
+// order is the same on every call. Ranges such as "qaa..qtz" have been
+// expanded when the registry was parsed, so each subtag of a range has its
+// own record.
+//
+// Each call scans the whole registry and returns a fresh slice, which holds
+// several thousand records for the "language" type. Callers needing the
+// result more than once should cache it.
+func (p *Parser) RecordsByType(t string) []Record {
+	var records []Record
+	for _, rec := range p.registry.Records {
+		if rec.Type == t {
+			records = append(records, rec)
+		}
+	}
+	slices.SortFunc(records, func(a, b Record) int {
+		return cmp.Or(cmp.Compare(a.Subtag, b.Subtag), cmp.Compare(a.Tag, b.Tag))
+	})
+	return records
+}
+
+// GrandfatheredTags returns every grandfathered tag of the registry, as
+// written in its Tag field, mapped to its Preferred-Value. Tags without a
+// preferred value, such as "i-enochian", map to an empty string.
+func (p *Parser) GrandfatheredTags() map[string]string {
+	return p.preferredValuesByType("grandfathered")
+}
+
+// RedundantTags returns every redundant tag of the registry, as written in its
+// Tag field, mapped to its Preferred-Value. Tags without a preferred value,
+// such as "sr-Latn", map to an empty string.
+func (p *Parser) RedundantTags() map[string]string {
+	return p.preferredValuesByType("redundant")
+}
+
+// PreferredValue returns the Preferred-Value of a grandfathered or redundant
+// tag, which is its canonical replacement, e.g., "tlh" for "i-klingon" or
+// "cmn-Hans" for "zh-cmn-Hans". Tags kept as-is by Parse can then be reported
+// as deprecated without calling ParseAndNormalize. It returns false for tags
+// without a replacement, such as "i-enochian", and for tags that are neither
+// grandfathered nor redundant.
+func (p *Parser) PreferredValue(lt LanguageTag) (string, bool) {
+	rec, ok := p.registry.Records[strings.ToLower(lt.tag)]
+	if !ok || !rec.IsGrandfathered() || rec.PreferredValue == "" {
+		return "", false
+	}
+	return rec.PreferredValue, true
+}
+
+// DeprecatedSubtags returns the language, script, region and variant subtags
+// of lt whose registry records are deprecated, in the order in which they
+// appear in the tag, e.g., ["iw", "ZR"] for "iw-ZR". This allows datasets to
+// be linted without canonicalizing them. It returns nil if no subtag is
+// deprecated. Whole grandfathered and redundant tags are not considered; see
+// PreferredValue for them.
+func (p *Parser) DeprecatedSubtags(lt LanguageTag) []string {
+	var deprecated []string
+	check := func(subtag, subtagType string) {
+		key := subtagType + ":" + strings.ToLower(subtag)
+		if rec, ok := p.registry.Records[key]; ok && rec.Deprecated != "" {
+			deprecated = append(deprecated, subtag)
+		}
+	}
+
+	check(lt.PrimaryLanguage(), "language")
+	if script, ok := lt.Script(); ok {
+		check(script, "script")
+	}
+	if region, ok := lt.Region(); ok {
+		check(region, "region")
+	}
+	for _, variant := range lt.VariantSubtags() {
+		check(variant, "variant")
+	}
+	return deprecated
+}
+
+// preferredValuesByType maps the Tag of every record of the given type to its
+// Preferred-Value.
+func (p *Parser) preferredValuesByType(t string) map[string]string {
+	tags := make(map[string]string)
+	for _, rec := range p.registry.Records {
+		if rec.Type == t {
+			tags[rec.Tag] = rec.PreferredValue
+		}
+	}
+	return tags
+}
+
+// AddRecord inserts a record into the registry of the parser, replacing any
+// record of the same type for the same subtag or tag. This allows, for
+// instance, private deployments to make some private use subtags valid. As in
+// ParseRegistry, a range such as "qaa..qtz" in the Subtag or Tag field is
+// expanded into one record per subtag. It returns an error if the record has
+// no Type, has neither a Subtag nor a Tag, or holds an invalid range.
+//
+// The registry is not guarded against concurrent access: AddRecord must not be
+// called while the parser is being used by other goroutines. For this reason,
+// it returns ErrSharedParser for the parser returned by Default.
+func (p *Parser) AddRecord(rec Record) error {
+	if p.shared {
+		return ErrSharedParser
+	}
+	if rec.Type == "" || (rec.Subtag == "" && rec.Tag == "") {
+		return errors.New("a registry record must have a type and either a subtag or a tag")
+	}
+	return processAndAddRecord(p.registry, rec)
+}
+
+// MergeRegistry inserts every record of another registry into the registry of
+// the parser, as AddRecord does. Records of other replace the existing records
+// of the same type for the same subtag or tag, and records AddRecord would
+// reject are skipped. The File-Date of the parser's registry is kept.
+//
+// Like AddRecord, MergeRegistry must not be called while the parser is being
+// used by other goroutines, and it returns ErrSharedParser for the parser
+// returned by Default.
+func (p *Parser) MergeRegistry(other *Registry) error {
+	if p.shared {
+		return ErrSharedParser
+	}
+	if other == nil {
+		return nil
+	}
+	for _, rec := range other.Records {
+		_ = p.AddRecord(rec)
+	}
+	return nil
+}
@@ -18,6 +18,9 @@ limitations under the License.
 package langtag
 
 import (
+	"errors"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -90,3 +93,359 @@ func TestRecord_IsGrandfathered(t *testing.T) {
 		})
 	}
 }
+
+// TestParser_RegistryDate tests that the File-Date of the registry is exposed.
+func TestParser_RegistryDate(t *testing.T) {
+	parser, err := NewParserFromRegistry(strings.NewReader("File-Date: 2024-07-25\n%%\nType: language\nSubtag: en\n"))
+	if err != nil {
+		t.Fatalf("NewParserFromRegistry() returned an unexpected error: %v", err)
+	}
+	if got := parser.RegistryDate(); got != "2024-07-25" {
+		t.Errorf("RegistryDate() = %q; want %q", got, "2024-07-25")
+	}
+
+	parser, err = NewParserFromRegistry(strings.NewReader("Type: language\nSubtag: en\n"))
+	if err != nil {
+		t.Fatalf("NewParserFromRegistry() returned an unexpected error: %v", err)
+	}
+	if got := parser.RegistryDate(); got != "" {
+		t.Errorf("RegistryDate() = %q; want an empty string", got)
+	}
+
+	if p.RegistryDate() == "" {
+		t.Error("RegistryDate() of the embedded registry should not be empty")
+	}
+}
+
+// TestParser_SuppressScript tests the lookup of the Suppress-Script field of
+// primary languages.
+func TestParser_SuppressScript(t *testing.T) {
+	tests := []struct {
+		language string
+		want     string
+		wantOK   bool
+	}{
+		{language: "en", want: "Latn", wantOK: true},
+		{language: "EN", want: "Latn", wantOK: true},
+		{language: "ru", want: "Cyrl", wantOK: true},
+		{language: "ja", want: "Jpan", wantOK: true},
+		{language: "sr", want: "", wantOK: false},
+		{language: "zh", want: "", wantOK: false},
+		{language: "Latn", want: "", wantOK: false},
+		{language: "", want: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			got, ok := p.SuppressScript(tt.language)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("SuppressScript(%q) = (%q, %v); want (%q, %v)", tt.language, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestParser_RecordsByType tests the listing of the registry records of a type.
+func TestParser_RecordsByType(t *testing.T) {
+	parser := newTestParser(map[string]Record{
+		"language:fr":  {Type: "language", Subtag: "fr"},
+		"language:en":  {Type: "language", Subtag: "en"},
+		"language:qaa": {Type: "language", Subtag: "qaa"},
+		"language:qab": {Type: "language", Subtag: "qab"},
+		"script:latn":  {Type: "script", Subtag: "Latn"},
+		"region:us":    {Type: "region", Subtag: "US"},
+		"region:fr":    {Type: "region", Subtag: "FR"},
+		"i-klingon":    {Type: "grandfathered", Tag: "i-klingon"},
+		"art-lojban":   {Type: "grandfathered", Tag: "art-lojban"},
+		"zh-hant":      {Type: "redundant", Tag: "zh-Hant"},
+	})
+
+	testCases := []struct {
+		recordType string
+		expected   []string
+	}{
+		{recordType: "language", expected: []string{"en", "fr", "qaa", "qab"}},
+		{recordType: "script", expected: []string{"Latn"}},
+		{recordType: "region", expected: []string{"FR", "US"}},
+		{recordType: "grandfathered", expected: []string{"art-lojban", "i-klingon"}},
+		{recordType: "redundant", expected: []string{"zh-Hant"}},
+		{recordType: "variant", expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.recordType, func(t *testing.T) {
+			records := parser.RecordsByType(tc.recordType)
+			if len(records) != len(tc.expected) {
+				t.Fatalf("RecordsByType(%q) returned %d records; want %d",
+					tc.recordType, len(records), len(tc.expected))
+			}
+			for i, rec := range records {
+				name := rec.Subtag
+				if name == "" {
+					name = rec.Tag
+				}
+				if name != tc.expected[i] {
+					t.Errorf("RecordsByType(%q)[%d] = %q; want %q", tc.recordType, i, name, tc.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestParser_RecordsByType_Embedded checks the listing against the embedded
+// registry, which expands the private use range "qaa..qtz" of languages.
+func TestParser_RecordsByType_Embedded(t *testing.T) {
+	languages := p.RecordsByType("language")
+	if len(languages) < 8000 {
+		t.Fatalf("RecordsByType(\"language\") returned %d records; want at least 8000", len(languages))
+	}
+	if !slices.IsSortedFunc(languages, func(a, b Record) int { return strings.Compare(a.Subtag, b.Subtag) }) {
+		t.Error("RecordsByType(\"language\") is not sorted by subtag")
+	}
+	if idx := slices.IndexFunc(languages, func(r Record) bool { return r.Subtag == "qtz" }); idx < 0 {
+		t.Error("RecordsByType(\"language\") is missing the expanded private use subtag 'qtz'")
+	}
+}
+
+// TestParser_GrandfatheredAndRedundantTags checks the listings of
+// grandfathered and redundant tags of the embedded registry.
+func TestParser_GrandfatheredAndRedundantTags(t *testing.T) {
+	grandfathered := p.GrandfatheredTags()
+	redundant := p.RedundantTags()
+
+	tests := []struct {
+		name  string
+		tags  map[string]string
+		tag   string
+		value string
+	}{
+		{name: "Regular grandfathered", tags: grandfathered, tag: "art-lojban", value: "jbo"},
+		{name: "Irregular grandfathered", tags: grandfathered, tag: "i-klingon", value: "tlh"},
+		{name: "Grandfathered without preferred value", tags: grandfathered, tag: "i-enochian", value: ""},
+		{name: "Redundant", tags: redundant, tag: "zh-cmn-Hans", value: "cmn-Hans"},
+		{name: "Redundant without preferred value", tags: redundant, tag: "sr-Latn", value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := tt.tags[tt.tag]
+			if !ok {
+				t.Fatalf("%q is missing from the listing", tt.tag)
+			}
+			if value != tt.value {
+				t.Errorf("%q maps to %q, want %q", tt.tag, value, tt.value)
+			}
+		})
+	}
+
+	if _, ok := grandfathered["zh-cmn-Hans"]; ok {
+		t.Error("GrandfatheredTags() contains the redundant tag \"zh-cmn-Hans\"")
+	}
+	if _, ok := redundant["art-lojban"]; ok {
+		t.Error("RedundantTags() contains the grandfathered tag \"art-lojban\"")
+	}
+	if len(grandfathered) != 26 {
+		t.Errorf("GrandfatheredTags() returned %d tags, want 26", len(grandfathered))
+	}
+}
+
+// TestParser_PreferredValue tests the lookup of the replacement of a
+// grandfathered or redundant tag.
+func TestParser_PreferredValue(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   string
+		wantOk bool
+	}{
+		{tag: "i-klingon", want: "tlh", wantOk: true},
+		{tag: "art-lojban", want: "jbo", wantOk: true},
+		{tag: "I-KLINGON", want: "tlh", wantOk: true},
+		{tag: "zh-cmn-Hans", want: "cmn-Hans", wantOk: true},
+		{tag: "i-enochian"},
+		{tag: "sr-Latn"},
+		{tag: "en-US"},
+		{tag: "iw"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, ok := p.PreferredValue(mustParse(t, tt.tag))
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("PreferredValue() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+// TestParser_DeprecatedSubtags tests the detection of deprecated subtags,
+// which are reported as written in the tag, after the case normalization done
+// by Parse.
+func TestParser_DeprecatedSubtags(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{tag: "iw-zr", want: []string{"iw", "ZR"}},
+		{tag: "IW-Hebr-ZR", want: []string{"iw", "ZR"}},
+		{tag: "en-BU", want: []string{"BU"}},
+		{tag: "sgn-BR", want: nil},
+		{tag: "de-CH-1901", want: nil},
+		{tag: "en-heploc", want: []string{"heploc"}},
+		{tag: "he-IL", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := p.DeprecatedSubtags(mustParse(t, tt.tag)); !slices.Equal(got, tt.want) {
+				t.Errorf("DeprecatedSubtags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRegistry_Validate tests the detection of dangling references between
+// registry records.
+func TestRegistry_Validate(t *testing.T) {
+	t.Run("Embedded registry", func(t *testing.T) {
+		if errs := p.registry.Validate(); len(errs) != 0 {
+			t.Errorf("Validate() = %v, want no error", errs)
+		}
+	})
+
+	t.Run("Dangling references", func(t *testing.T) {
+		registry := &Registry{Records: map[string]Record{
+			"language:zh":  {Type: "language", Subtag: "zh", SuppressScript: "Hans"},
+			"language:cmn": {Type: "language", Subtag: "cmn", Macrolanguage: "zh"},
+			"extlang:cmn":  {Type: "extlang", Subtag: "cmn", Prefix: []string{"zh"}, PreferredValue: "cmn"},
+			"extlang:yue":  {Type: "extlang", Subtag: "yue", Prefix: []string{"zz"}, PreferredValue: "yue"},
+			"region:bu":    {Type: "region", Subtag: "BU", PreferredValue: "MM"},
+			"zh-cmn":       {Type: "redundant", Tag: "zh-cmn", PreferredValue: "cmn"},
+		}}
+
+		want := []string{
+			`the extlang record "yue" has the prefix "zz"`,
+			`the extlang record "yue" has the preferred value "yue"`,
+			`the language record "zh" has the suppressed script "Hans"`,
+			`the region record "BU" has the preferred value "MM"`,
+		}
+		errs := registry.Validate()
+		if len(errs) != len(want) {
+			t.Fatalf("Validate() returned %d errors, want %d: %v", len(errs), len(want), errs)
+		}
+		for i, err := range errs {
+			if !errors.Is(err, ErrInconsistentRegistry) {
+				t.Errorf("error %d = %v, want it to wrap ErrInconsistentRegistry", i, err)
+			}
+			if !strings.HasSuffix(err.Error(), want[i]) {
+				t.Errorf("error %d = %q, want it to end with %q", i, err, want[i])
+			}
+		}
+	})
+}
+
+// TestParser_AddRecord tests that added records make new subtags valid.
+func TestParser_AddRecord(t *testing.T) {
+	parser := newTestParser(map[string]Record{
+		"language:en": {Type: "language", Subtag: "en"},
+	})
+
+	if _, err := parser.ParseAndNormalize("qtx"); err == nil {
+		t.Fatal("ParseAndNormalize(\"qtx\") should fail before the record is added")
+	}
+
+	if err := parser.AddRecord(Record{Type: "language", Subtag: "qtx", Description: []string{"Custom"}}); err != nil {
+		t.Fatalf("AddRecord() returned an unexpected error: %v", err)
+	}
+	if _, ok := parser.registry.Records["language:qtx"]; !ok {
+		t.Error("AddRecord() did not insert the record under the key 'language:qtx'")
+	}
+	if _, err := parser.ParseAndNormalize("qtx"); err != nil {
+		t.Errorf("ParseAndNormalize(\"qtx\") returned an unexpected error: %v", err)
+	}
+
+	if err := parser.AddRecord(Record{Type: "region", Subtag: "XA..XC"}); err != nil {
+		t.Fatalf("AddRecord() returned an unexpected error for a range: %v", err)
+	}
+	for _, key := range []string{"region:xa", "region:xb", "region:xc"} {
+		if _, ok := parser.registry.Records[key]; !ok {
+			t.Errorf("AddRecord() did not expand the range into the key %q", key)
+		}
+	}
+	if _, err := parser.ParseAndNormalize("en-XB"); err != nil {
+		t.Errorf("ParseAndNormalize(\"en-XB\") returned an unexpected error: %v", err)
+	}
+
+	if err := parser.AddRecord(Record{Type: "grandfathered", Tag: "i-custom"}); err != nil {
+		t.Fatalf("AddRecord() returned an unexpected error for a tag: %v", err)
+	}
+	if _, ok := parser.registry.Records["i-custom"]; !ok {
+		t.Error("AddRecord() did not insert the grandfathered record under the key 'i-custom'")
+	}
+}
+
+// TestParser_AddRecord_Errors tests that invalid records are rejected.
+func TestParser_AddRecord_Errors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		record Record
+	}{
+		{name: "Missing type", record: Record{Subtag: "qtx"}},
+		{name: "Missing subtag and tag", record: Record{Type: "language"}},
+		{name: "Invalid range", record: Record{Type: "region", Subtag: "123..abc"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := newTestParser(map[string]Record{})
+			if err := parser.AddRecord(tc.record); err == nil {
+				t.Error("AddRecord() should have failed but did not")
+			}
+			if len(parser.registry.Records) != 0 {
+				t.Errorf("AddRecord() inserted %d records on failure", len(parser.registry.Records))
+			}
+		})
+	}
+}
+
+// TestParser_MergeRegistry tests that the records of another registry are
+// inserted, and that invalid ones are skipped.
+func TestParser_MergeRegistry(t *testing.T) {
+	parser := newTestParser(map[string]Record{
+		"language:en": {Type: "language", Subtag: "en", Description: []string{"English"}},
+	})
+
+	err := parser.MergeRegistry(&Registry{
+		FileDate: "2030-01-01",
+		Records: map[string]Record{
+			"language:en":  {Type: "language", Subtag: "en", Description: []string{"Overridden"}},
+			"language:qtx": {Type: "language", Subtag: "qtx"},
+			"range":        {Type: "script", Subtag: "Qaaa..Qaab"},
+			"invalid":      {Type: "region", Subtag: "123..abc"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("MergeRegistry() returned an unexpected error: %v", err)
+	}
+	if err = parser.MergeRegistry(nil); err != nil {
+		t.Fatalf("MergeRegistry(nil) returned an unexpected error: %v", err)
+	}
+
+	expectedKeys := []string{"language:en", "language:qtx", "script:qaaa", "script:qaab"}
+	if len(parser.registry.Records) != len(expectedKeys) {
+		t.Errorf("expected %d records after merge, got %d", len(expectedKeys), len(parser.registry.Records))
+	}
+	for _, key := range expectedKeys {
+		if _, ok := parser.registry.Records[key]; !ok {
+			t.Errorf("MergeRegistry() did not insert the key %q", key)
+		}
+	}
+	if got := parser.registry.Records["language:en"].Description[0]; got != "Overridden" {
+		t.Errorf("MergeRegistry() did not replace the existing record, description = %q", got)
+	}
+	if parser.registry.FileDate != "2023-01-01" {
+		t.Errorf("MergeRegistry() changed the File-Date to %q", parser.registry.FileDate)
+	}
+	if _, err := parser.ParseAndNormalize("qtx-Qaab"); err != nil {
+		t.Errorf("ParseAndNormalize(\"qtx-Qaab\") returned an unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"bytes"
+	_ "embed" // Note the blank import for go:embed
+	"encoding/gob"
+	"hash/crc32"
+	"slices"
+)
+
+// embeddedRegistrySnapshot holds the embedded registry, already parsed and
+// encoded by encodeRegistrySnapshot. It is regenerated with go generate.
+//
+//go:embed language-subtag-registry.gob
+var embeddedRegistrySnapshot []byte
+
+// registrySnapshot is the serialized form of a parsed Registry. The records
+// are stored as parallel slices sorted by key, which keeps the encoding
+// deterministic, and the checksum of the registry file they were parsed from
+// allows detecting a stale snapshot.
+type registrySnapshot struct {
+	SourceChecksum uint32
+	FileDate       string
+	Keys           []string
+	Records        []Record
+}
+
+// encodeRegistrySnapshot serializes a registry parsed from the given registry
+// file content.
+func encodeRegistrySnapshot(registry *Registry, source []byte) ([]byte, error) {
+	keys := make([]string, 0, len(registry.Records))
+	for key := range registry.Records {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	snapshot := registrySnapshot{
+		SourceChecksum: crc32.ChecksumIEEE(source),
+		FileDate:       registry.FileDate,
+		Keys:           keys,
+		Records:        make([]Record, len(keys)),
+	}
+	for i, key := range keys {
+		snapshot.Records[i] = registry.Records[key]
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&snapshot); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeRegistrySnapshot deserializes a registry encoded by
+// encodeRegistrySnapshot. It returns false if the data cannot be decoded, or
+// if it was not generated from the given registry file content, in which case
+// the file must be parsed instead.
+func decodeRegistrySnapshot(data, source []byte) (*Registry, bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+	var snapshot registrySnapshot
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&snapshot); err != nil {
+		return nil, false
+	}
+	if snapshot.SourceChecksum != crc32.ChecksumIEEE(source) || len(snapshot.Keys) != len(snapshot.Records) {
+		return nil, false
+	}
+
+	registry := &Registry{
+		Records:  make(map[string]Record, len(snapshot.Keys)),
+		FileDate: snapshot.FileDate,
+	}
+	for i, key := range snapshot.Keys {
+		registry.Records[key] = snapshot.Records[i]
+	}
+	return registry, true
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"reflect"
+	"testing"
+)
+
+//nolint:gochecknoglobals // Test flags must be registered at package level.
+var updateSnapshot = flag.Bool("update-snapshot", false, "regenerate language-subtag-registry.gob")
+
+// TestRegistrySnapshot verifies that the embedded snapshot decodes to exactly
+// the registry parsed from the embedded text file. When run with the
+// -update-snapshot flag, it regenerates the snapshot first.
+func TestRegistrySnapshot(t *testing.T) {
+	parsed, err := ParseRegistry(bytes.NewReader(embeddedRegistryData))
+	if err != nil {
+		t.Fatalf("ParseRegistry() returned an unexpected error: %v", err)
+	}
+
+	snapshot := embeddedRegistrySnapshot
+	if *updateSnapshot {
+		snapshot, err = encodeRegistrySnapshot(parsed, embeddedRegistryData)
+		if err != nil {
+			t.Fatalf("encodeRegistrySnapshot() returned an unexpected error: %v", err)
+		}
+		if err = os.WriteFile("language-subtag-registry.gob", snapshot, 0o600); err != nil {
+			t.Fatalf("failed to write the snapshot: %v", err)
+		}
+	}
+
+	decoded, ok := decodeRegistrySnapshot(snapshot, embeddedRegistryData)
+	if !ok {
+		t.Fatal("the embedded snapshot is stale or corrupted; run go generate ./langtag")
+	}
+	if !reflect.DeepEqual(decoded, parsed) {
+		t.Error("the registry decoded from the snapshot differs from the parsed one")
+	}
+}
+
+// TestDecodeRegistrySnapshot_Invalid verifies that a snapshot is rejected
+// when it does not match the registry file or cannot be decoded.
+func TestDecodeRegistrySnapshot_Invalid(t *testing.T) {
+	source := []byte("File-Date: 2024-07-25\n%%\nType: language\nSubtag: en\n")
+	registry, err := ParseRegistry(bytes.NewReader(source))
+	if err != nil {
+		t.Fatalf("ParseRegistry() returned an unexpected error: %v", err)
+	}
+	data, err := encodeRegistrySnapshot(registry, source)
+	if err != nil {
+		t.Fatalf("encodeRegistrySnapshot() returned an unexpected error: %v", err)
+	}
+
+	if decoded, ok := decodeRegistrySnapshot(data, source); !ok || !reflect.DeepEqual(decoded, registry) {
+		t.Errorf("decodeRegistrySnapshot() = (%v, %v), want the encoded registry", decoded, ok)
+	}
+	if _, ok := decodeRegistrySnapshot(data, []byte("File-Date: 2025-01-01\n")); ok {
+		t.Error("decodeRegistrySnapshot() accepted a snapshot of another registry file")
+	}
+	if _, ok := decodeRegistrySnapshot(data[:len(data)/2], source); ok {
+		t.Error("decodeRegistrySnapshot() accepted truncated data")
+	}
+	if _, ok := decodeRegistrySnapshot(nil, source); ok {
+		t.Error("decodeRegistrySnapshot() accepted empty data")
+	}
+}
+
+// BenchmarkNewParser compares loading the embedded registry from its snapshot
+// with parsing its text file.
+func BenchmarkNewParser(b *testing.B) {
+	b.Run("Snapshot", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, ok := decodeRegistrySnapshot(embeddedRegistrySnapshot, embeddedRegistryData); !ok {
+				b.Fatal("the embedded snapshot is stale")
+			}
+		}
+	})
+	b.Run("Text", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := ParseRegistry(bytes.NewReader(embeddedRegistryData)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
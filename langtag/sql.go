@@ -0,0 +1,53 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Scan implements the sql.Scanner interface so that a LanguageTag can be read
+// directly from a database text column. It accepts string and []byte values,
+// which are validated and canonicalized like UnmarshalText does, so a tag
+// loaded from the database is always in canonical form. nil (SQL NULL) and an
+// empty string leave the LanguageTag as its zero value.
+//
+// Like UnmarshalText, Scan uses the shared parser returned by Default, and
+// returns its error if the embedded registry cannot be loaded.
+func (lt *LanguageTag) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*lt = LanguageTag{}
+		return nil
+	case string:
+		return lt.UnmarshalText([]byte(v))
+	case []byte:
+		return lt.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan value of type %T into a LanguageTag", src)
+	}
+}
+
+// Value implements the driver.Valuer interface, returning the language tag as
+// a string. The zero LanguageTag is stored as SQL NULL.
+func (lt LanguageTag) Value() (driver.Value, error) {
+	if lt.tag == "" {
+		return nil, nil //nolint:nilnil // A nil value is how SQL NULL is represented.
+	}
+	return lt.tag, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+// Compile-time checks that LanguageTag implements the database/sql interfaces.
+var (
+	_ sql.Scanner   = (*LanguageTag)(nil)
+	_ driver.Valuer = LanguageTag{}
+)
+
+// TestLanguageTag_Scan tests reading a LanguageTag from the values a database
+// driver may return.
+func TestLanguageTag_Scan(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      any
+		expected string
+		wantErr  bool
+	}{
+		{name: "String", src: "en-US", expected: "en-US"},
+		{name: "Bytes", src: []byte("sr-Latn-RS"), expected: "sr-Latn-RS"},
+		{name: "Canonicalized", src: "iw-il", expected: "he-IL"},
+		{name: "Nil", src: nil, expected: ""},
+		{name: "Empty string", src: "", expected: ""},
+		{name: "Invalid tag", src: "zz-US", wantErr: true},
+		{name: "Malformed tag", src: "en_US", wantErr: true},
+		{name: "Unsupported type", src: 42, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lt := mustParse(t, "fr")
+			err := lt.Scan(tc.src)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if lt.String() != tc.expected {
+				t.Errorf("Scan() produced '%s', want '%s'", lt.String(), tc.expected)
+			}
+		})
+	}
+}
+
+// TestLanguageTag_Value tests converting a LanguageTag into a database value,
+// and reading it back.
+func TestLanguageTag_Value(t *testing.T) {
+	lt := mustParseAndNormalize(t, "de-CH-1996")
+	v, err := lt.Value()
+	if err != nil {
+		t.Fatalf("Value() returned an unexpected error: %v", err)
+	}
+	if v != "de-CH-1996" {
+		t.Errorf("Value() = %v, want %q", v, "de-CH-1996")
+	}
+
+	var scanned LanguageTag
+	if err = scanned.Scan(v); err != nil {
+		t.Fatalf("Scan() returned an unexpected error: %v", err)
+	}
+	if scanned.String() != lt.String() || scanned.positions != lt.positions {
+		t.Errorf("round trip produced %q, want %q", scanned.String(), lt.String())
+	}
+
+	v, err = LanguageTag{}.Value()
+	if err != nil || v != nil {
+		t.Errorf("Value() of the zero LanguageTag = (%v, %v), want (nil, nil)", v, err)
+	}
+}
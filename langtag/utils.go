@@ -74,6 +74,26 @@ func isAlphanumeric(s string) bool {
 	return true
 }
 
+// isLowerASCII checks if a string contains no ASCII uppercase letter.
+func isLowerASCII(s string) bool {
+	for i := range len(s) {
+		if s[i] >= 'A' && s[i] <= 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// hasLowerASCII checks if a string contains an ASCII lowercase letter.
+func hasLowerASCII(s string) bool {
+	for i := range len(s) {
+		if s[i] >= 'a' && s[i] <= 'z' {
+			return true
+		}
+	}
+	return false
+}
+
 // writeTitleCase writes a string to a builder using title case (e.g., "Latn").
 func writeTitleCase(b *strings.Builder, s string) {
 	if len(s) == 0 {
@@ -0,0 +1,49 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import "encoding/xml"
+
+// MarshalXML implements the xml.Marshaler interface, encoding the language
+// tag as the character data of the element.
+func (lt *LanguageTag) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(lt.tag, start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface. Like UnmarshalJSON,
+// it performs a full validity check and canonicalizes the tag found in the
+// character data of the element. An empty element yields the zero value.
+func (lt *LanguageTag) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	return lt.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface, so that a
+// LanguageTag can be used as an attribute such as xml:lang.
+func (lt *LanguageTag) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: lt.tag}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface. The attribute
+// value is validated and canonicalized as with UnmarshalXML; an empty value,
+// which xml:lang uses to mean "no language", yields the zero value.
+func (lt *LanguageTag) UnmarshalXMLAttr(attr xml.Attr) error {
+	return lt.UnmarshalText([]byte(attr.Value))
+}
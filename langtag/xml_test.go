@@ -0,0 +1,112 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langtag
+
+import (
+	"encoding/xml"
+	"errors"
+	"testing"
+)
+
+// Compile-time checks that LanguageTag implements the encoding/xml interfaces.
+var (
+	_ xml.Marshaler       = (*LanguageTag)(nil)
+	_ xml.Unmarshaler     = (*LanguageTag)(nil)
+	_ xml.MarshalerAttr   = (*LanguageTag)(nil)
+	_ xml.UnmarshalerAttr = (*LanguageTag)(nil)
+)
+
+// xmlTitle carries a language tag both as an attribute and as an element.
+type xmlTitle struct {
+	XMLName  xml.Name    `xml:"title"`
+	Lang     LanguageTag `xml:"lang,attr"`
+	Fallback LanguageTag `xml:"fallback"`
+}
+
+// TestLanguageTag_XML tests encoding a LanguageTag as an XML attribute and
+// element, and decoding it back.
+func TestLanguageTag_XML(t *testing.T) {
+	in := xmlTitle{
+		Lang:     mustParseAndNormalize(t, "de-CH-1996"),
+		Fallback: mustParseAndNormalize(t, "en"),
+	}
+	data, err := xml.Marshal(&in)
+	if err != nil {
+		t.Fatalf("xml.Marshal() returned an unexpected error: %v", err)
+	}
+	if want := `<title lang="de-CH-1996"><fallback>en</fallback></title>`; string(data) != want {
+		t.Errorf("xml.Marshal() = %s, want %s", data, want)
+	}
+
+	var out xmlTitle
+	if err = xml.Unmarshal(data, &out); err != nil {
+		t.Fatalf("xml.Unmarshal() returned an unexpected error: %v", err)
+	}
+	if !out.Lang.Equal(in.Lang) || !out.Fallback.Equal(in.Fallback) {
+		t.Errorf("round trip produced %+v, want %+v", out, in)
+	}
+}
+
+// TestLanguageTag_UnmarshalXML tests that decoding canonicalizes tags and
+// rejects invalid ones, like UnmarshalJSON does.
+func TestLanguageTag_UnmarshalXML(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantLang     string
+		wantFallback string
+		wantErr      error
+	}{
+		{
+			name:         "Canonicalized",
+			data:         `<title lang="EN-latn-us"><fallback>iw</fallback></title>`,
+			wantLang:     "en-US",
+			wantFallback: "he",
+		},
+		{
+			name: "Empty",
+			data: `<title lang=""><fallback></fallback></title>`,
+		},
+		{
+			name:    "Invalid attribute",
+			data:    `<title lang="en-a-"><fallback>en</fallback></title>`,
+			wantErr: ErrEmptyExtension,
+		},
+		{
+			name:    "Invalid element",
+			data:    `<title lang="en"><fallback>en-a-</fallback></title>`,
+			wantErr: ErrEmptyExtension,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out xmlTitle
+			err := xml.Unmarshal([]byte(tt.data), &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("xml.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if out.Lang.String() != tt.wantLang || out.Fallback.String() != tt.wantFallback {
+				t.Errorf("xml.Unmarshal() got (%q, %q), want (%q, %q)",
+					out.Lang.String(), out.Fallback.String(), tt.wantLang, tt.wantFallback)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uritemplate
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// operator describes how an expression is expanded, as per the table of
+// RFC 6570, Appendix A.
+type operator struct {
+	// first is written before the first defined variable.
+	first string
+	// sep separates the defined variables, and the items of exploded values.
+	sep string
+	// named tells whether the variable names are written as "name=value".
+	named bool
+	// ifEmpty is written after the name of a named variable with an empty value.
+	ifEmpty string
+	// allowReserved tells whether reserved characters are kept as-is.
+	allowReserved bool
+}
+
+// simpleOperator is the operator of expressions without an operator character.
+//
+//nolint:gochecknoglobals // A read-only table entry shared by all expansions.
+var simpleOperator = operator{sep: ","}
+
+// operators maps each operator character to its expansion rules.
+//
+//nolint:gochecknoglobals // A read-only lookup table shared by all expansions.
+var operators = map[byte]*operator{
+	'+': {sep: ",", allowReserved: true},
+	'#': {first: "#", sep: ",", allowReserved: true},
+	'.': {first: ".", sep: "."},
+	'/': {first: "/", sep: "/"},
+	';': {first: ";", sep: ";", named: true},
+	'?': {first: "?", sep: "&", named: true, ifEmpty: "="},
+	'&': {first: "&", sep: "&", named: true, ifEmpty: "="},
+}
+
+// encode writes s, percent-encoding every character that is not unreserved
+// and, if allowReserved is set, not reserved either. In the latter case,
+// existing percent-encoded triplets are kept as-is, as per RFC 6570,
+// Section 3.2.3.
+func encode(b *strings.Builder, s string, allowReserved bool) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case isUnreserved(c):
+			b.WriteByte(c)
+			i++
+		case allowReserved && isReserved(c):
+			b.WriteByte(c)
+			i++
+		case allowReserved && c == '%' && i+2 < len(s) && isHexDigit(s[i+1]) && isHexDigit(s[i+2]):
+			b.WriteString(s[i : i+3])
+			i += 3
+		default:
+			// Non-ASCII characters are encoded as their UTF-8 octets.
+			_, size := utf8.DecodeRuneInString(s[i:])
+			for j := i; j < i+size; j++ {
+				fmt.Fprintf(b, "%%%02X", s[j])
+			}
+			i += size
+		}
+	}
+}
+
+// hasValidPercentEncoding reports whether every '%' in s starts a
+// percent-encoded triplet.
+func hasValidPercentEncoding(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		if i+2 >= len(s) || !isHexDigit(s[i+1]) || !isHexDigit(s[i+2]) {
+			return false
+		}
+		i += 2
+	}
+	return true
+}
+
+// isUnreserved reports whether c is an unreserved character of RFC 3986.
+func isUnreserved(c byte) bool {
+	return isAlphaNum(c) || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// isReserved reports whether c is a reserved character (gen-delims or
+// sub-delims) of RFC 3986.
+func isReserved(c byte) bool {
+	return strings.IndexByte(":/?#[]@!$&'()*+,;=", c) != -1
+}
+
+// isAlphaNum reports whether c is an ASCII letter or digit.
+func isAlphaNum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// isHexDigit reports whether c is an ASCII hexadecimal digit.
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package uritemplate implements URI Templates as defined by RFC 6570.
+//
+// A template such as "http://example.com/{id}{?q,page}" is parsed once with
+// Parse, and can then be expanded any number of times with Expand, which
+// substitutes the variables and validates the result as an IRI reference with
+// the iri package.
+//
+// # Key Features
+//
+//   - Full Level 4 Support: All the operators ("+", "#", ".", "/", ";", "?",
+//     and "&"), prefix modifiers (e.g., "{var:3}"), and explode modifiers
+//     (e.g., "{list*}") are supported.
+//   - Composite Values: Variables can be strings, numbers, booleans, lists,
+//     or maps. The keys of a map are expanded in lexicographic order, so that
+//     the expansion is deterministic.
+//   - Validated Output: The expansion is percent-encoded according to each
+//     operator and then parsed with iri.ParseRef.
+package uritemplate
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jplu/trident/iri"
+)
+
+const (
+	// maxPrefixLength is the largest prefix modifier allowed by RFC 6570,
+	// Section 2.4.1.
+	maxPrefixLength = 9999
+)
+
+// Errors that can occur when parsing or expanding a template.
+var (
+	ErrUnclosedExpression = errors.New("a template expression is not closed by '}'")
+	ErrInvalidExpression  = errors.New("a template expression is malformed")
+	ErrInvalidLiteral     = errors.New("a template literal contains a character not allowed")
+	ErrPrefixOnComposite  = errors.New("a prefix modifier cannot be applied to a list or map value")
+	ErrUnsupportedValue   = errors.New("a template variable has a value of an unsupported type")
+)
+
+// Template is a parsed URI Template. It is immutable and safe for concurrent
+// use, so a template can be parsed once and expanded many times.
+type Template struct {
+	raw   string
+	parts []part
+}
+
+// part is either a literal string or an expression of a template.
+type part struct {
+	literal    string
+	expression *expression
+}
+
+// expression is a template expression, enclosed in braces.
+type expression struct {
+	op       *operator
+	varspecs []varspec
+}
+
+// varspec is a variable reference of an expression, with its modifier.
+type varspec struct {
+	name    string
+	explode bool
+	prefix  int
+}
+
+// Parse parses a URI Template. The literals and the expressions are checked
+// against the grammar of RFC 6570, Section 2, and an error wrapping one of the
+// package errors is returned if they do not conform.
+func Parse(template string) (*Template, error) {
+	t := &Template{raw: template}
+	rest := template
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start == -1 {
+			start = len(rest)
+		}
+		if start > 0 {
+			literal, err := parseLiteral(rest[:start])
+			if err != nil {
+				return nil, err
+			}
+			t.parts = append(t.parts, part{literal: literal})
+		}
+		if start == len(rest) {
+			break
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end == -1 {
+			return nil, fmt.Errorf("%w: %s", ErrUnclosedExpression, rest[start:])
+		}
+		expr, err := parseExpression(rest[start+1 : start+end])
+		if err != nil {
+			return nil, err
+		}
+		t.parts = append(t.parts, part{expression: expr})
+		rest = rest[start+end+1:]
+	}
+	return t, nil
+}
+
+// String returns the template as it was given to Parse.
+func (t *Template) String() string {
+	return t.raw
+}
+
+// Expand substitutes the variables of the template with the given values and
+// returns the resulting IRI reference.
+//
+// A value can be a string, a boolean, an integer or floating-point number, a
+// fmt.Stringer, a list ([]string or []any), or a map (map[string]string or
+// map[string]any). Variables that are missing, nil, or bound to an empty list
+// or map are undefined and are skipped, as per RFC 6570, Section 3.2.1.
+func (t *Template) Expand(vars map[string]any) (*iri.Ref, error) {
+	var b strings.Builder
+	b.Grow(len(t.raw))
+	for _, p := range t.parts {
+		if p.expression == nil {
+			b.WriteString(p.literal)
+			continue
+		}
+		if err := p.expression.expand(&b, vars); err != nil {
+			return nil, err
+		}
+	}
+	return iri.ParseRef(b.String())
+}
+
+// parseLiteral checks the literal characters of a template and returns them
+// encoded as they must appear in the expansion: characters that are not
+// allowed in a URI are percent-encoded, as per RFC 6570, Section 3.1.
+func parseLiteral(s string) (string, error) {
+	for _, r := range s {
+		if r <= ' ' || r == 0x7F || strings.ContainsRune("\"'<>\\^`{|}", r) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidLiteral, r)
+		}
+	}
+	if strings.Contains(s, "%") && !hasValidPercentEncoding(s) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidLiteral, s)
+	}
+	var b strings.Builder
+	encode(&b, s, true)
+	return b.String(), nil
+}
+
+// parseExpression parses the content of an expression, without its braces.
+func parseExpression(s string) (*expression, error) {
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty expression", ErrInvalidExpression)
+	}
+	expr := &expression{op: &simpleOperator}
+	if op, ok := operators[s[0]]; ok {
+		expr.op = op
+		s = s[1:]
+	} else if strings.IndexByte("=,!@|", s[0]) != -1 {
+		return nil, fmt.Errorf("%w: reserved operator '%c'", ErrInvalidExpression, s[0])
+	}
+
+	for spec := range strings.SplitSeq(s, ",") {
+		v, err := parseVarspec(spec)
+		if err != nil {
+			return nil, err
+		}
+		expr.varspecs = append(expr.varspecs, v)
+	}
+	return expr, nil
+}
+
+// parseVarspec parses a variable name followed by an optional modifier.
+func parseVarspec(s string) (varspec, error) {
+	var v varspec
+	switch {
+	case strings.HasSuffix(s, "*"):
+		v.explode = true
+		s = s[:len(s)-1]
+	case strings.Contains(s, ":"):
+		var length string
+		s, length, _ = strings.Cut(s, ":")
+		prefix, err := strconv.Atoi(length)
+		if err != nil || prefix < 1 || prefix > maxPrefixLength || length[0] == '0' {
+			return v, fmt.Errorf("%w: invalid prefix '%s'", ErrInvalidExpression, length)
+		}
+		v.prefix = prefix
+	}
+	if !isValidVarname(s) {
+		return v, fmt.Errorf("%w: invalid variable name '%s'", ErrInvalidExpression, s)
+	}
+	v.name = s
+	return v, nil
+}
+
+// isValidVarname reports whether s is a variable name, made of ALPHA, DIGIT,
+// '_', and percent-encoded triplets, with single dots between characters.
+func isValidVarname(s string) bool {
+	if s == "" || s[0] == '.' || s[len(s)-1] == '.' || strings.Contains(s, "..") {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%':
+			if i+2 >= len(s) || !isHexDigit(s[i+1]) || !isHexDigit(s[i+2]) {
+				return false
+			}
+			i += 2
+		case c == '_' || c == '.' || isAlphaNum(c):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// expand writes the expansion of the expression, following the algorithm of
+// RFC 6570, Appendix A.
+func (e *expression) expand(b *strings.Builder, vars map[string]any) error {
+	first := true
+	for _, v := range e.varspecs {
+		val, err := lookup(vars, v.name)
+		if err != nil {
+			return err
+		}
+		if val.isUndefined() {
+			continue
+		}
+		if first {
+			b.WriteString(e.op.first)
+			first = false
+		} else {
+			b.WriteString(e.op.sep)
+		}
+		if err = e.expandValue(b, v, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandValue writes the expansion of a single, defined variable.
+func (e *expression) expandValue(b *strings.Builder, v varspec, val value) error {
+	switch {
+	case val.list == nil && val.keys == nil:
+		e.expandString(b, v, val.str)
+	case v.prefix > 0:
+		return fmt.Errorf("%w: %s", ErrPrefixOnComposite, v.name)
+	case v.explode:
+		e.expandExploded(b, v, val)
+	default:
+		e.expandComposite(b, v, val)
+	}
+	return nil
+}
+
+// expandString writes the expansion of a string value.
+func (e *expression) expandString(b *strings.Builder, v varspec, s string) {
+	if e.op.named {
+		b.WriteString(v.name)
+		if s == "" {
+			b.WriteString(e.op.ifEmpty)
+			return
+		}
+		b.WriteByte('=')
+	}
+	if v.prefix > 0 {
+		s = truncateRunes(s, v.prefix)
+	}
+	encode(b, s, e.op.allowReserved)
+}
+
+// expandComposite writes the expansion of a list or map value without the
+// explode modifier: the items, or the keys and values, are joined with ','.
+func (e *expression) expandComposite(b *strings.Builder, v varspec, val value) {
+	if e.op.named {
+		b.WriteString(v.name)
+		b.WriteByte('=')
+	}
+	if val.list != nil {
+		for i, item := range val.list {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			encode(b, item, e.op.allowReserved)
+		}
+		return
+	}
+	for i, key := range val.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		encode(b, key, e.op.allowReserved)
+		b.WriteByte(',')
+		encode(b, val.values[i], e.op.allowReserved)
+	}
+}
+
+// expandExploded writes the expansion of a list or map value with the
+// explode modifier: each item is written separately, with the operator
+// separator. Map items are named by their keys, and list items are named by
+// the variable name only when the operator is named.
+func (e *expression) expandExploded(b *strings.Builder, v varspec, val value) {
+	items := val.list
+	if items == nil {
+		items = val.values
+	}
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString(e.op.sep)
+		}
+		switch {
+		case val.list == nil:
+			encode(b, val.keys[i], e.op.allowReserved)
+		case e.op.named:
+			b.WriteString(v.name)
+		default:
+			encode(b, item, e.op.allowReserved)
+			continue
+		}
+		// As in RFC 6570, Appendix A, only named operators use ifemp: the
+		// others always write "=", even before an empty value.
+		if item == "" && e.op.named {
+			b.WriteString(e.op.ifEmpty)
+			continue
+		}
+		b.WriteByte('=')
+		encode(b, item, e.op.allowReserved)
+	}
+}
+
+// truncateRunes returns the first n characters of s.
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	i := 0
+	for j := range s {
+		if i == n {
+			return s[:j]
+		}
+		i++
+	}
+	return s
+}
@@ -0,0 +1,358 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//nolint:testpackage // This is a white-box test file for an internal package. It needs to be in the same package to test unexported functions.
+package uritemplate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jplu/trident/iri"
+)
+
+// rfcVariables returns the variables used by the examples of RFC 6570,
+// Section 3.2.
+func rfcVariables() map[string]any {
+	return map[string]any{
+		"count":      []string{"one", "two", "three"},
+		"dom":        []string{"example", "com"},
+		"dub":        "me/too",
+		"hello":      "Hello World!",
+		"half":       "50%",
+		"var":        "value",
+		"who":        "fred",
+		"base":       "http://example.com/home/",
+		"path":       "/foo/bar",
+		"list":       []string{"red", "green", "blue"},
+		"keys":       map[string]string{"semi": ";", "dot": ".", "comma": ","},
+		"v":          "6",
+		"x":          "1024",
+		"y":          "768",
+		"empty":      "",
+		"empty_keys": map[string]string{},
+		"undef":      nil,
+	}
+}
+
+// TestTemplate_Expand_RFCExamples tests the expansion of the examples of
+// RFC 6570, Section 3.2. As the keys of a map are expanded in lexicographic
+// order, the expected results for "keys" list "comma", "dot", then "semi".
+func TestTemplate_Expand_RFCExamples(t *testing.T) {
+	testCases := map[string]string{
+		// Section 3.2.1: Variable Expansion.
+		"{count}":   "one,two,three",
+		"{count*}":  "one,two,three",
+		"{/count}":  "/one,two,three",
+		"{/count*}": "/one/two/three",
+		"{;count}":  ";count=one,two,three",
+		"{;count*}": ";count=one;count=two;count=three",
+		"{?count}":  "?count=one,two,three",
+		"{?count*}": "?count=one&count=two&count=three",
+		"{&count*}": "&count=one&count=two&count=three",
+		// Section 3.2.2: Simple String Expansion.
+		"{var}":       "value",
+		"{hello}":     "Hello%20World%21",
+		"{half}":      "50%25",
+		"O{empty}X":   "OX",
+		"O{undef}X":   "OX",
+		"{x,y}":       "1024,768",
+		"{x,hello,y}": "1024,Hello%20World%21,768",
+		"?{x,empty}":  "?1024,",
+		"?{x,undef}":  "?1024",
+		"?{undef,y}":  "?768",
+		"{var:3}":     "val",
+		"{var:30}":    "value",
+		"{list}":      "red,green,blue",
+		"{list*}":     "red,green,blue",
+		"{keys}":      "comma,%2C,dot,.,semi,%3B",
+		"{keys*}":     "comma=%2C,dot=.,semi=%3B",
+		// Section 3.2.3: Reserved Expansion.
+		"{+var}":              "value",
+		"{+hello}":            "Hello%20World!",
+		"{+half}":             "50%25",
+		"{base}index":         "http%3A%2F%2Fexample.com%2Fhome%2Findex",
+		"{+base}index":        "http://example.com/home/index",
+		"O{+empty}X":          "OX",
+		"O{+undef}X":          "OX",
+		"{+path}/here":        "/foo/bar/here",
+		"here?ref={+path}":    "here?ref=/foo/bar",
+		"up{+path}{var}/here": "up/foo/barvalue/here",
+		"{+x,hello,y}":        "1024,Hello%20World!,768",
+		"{+path,x}/here":      "/foo/bar,1024/here",
+		"{+path:6}/here":      "/foo/b/here",
+		"{+list}":             "red,green,blue",
+		"{+list*}":            "red,green,blue",
+		"{+keys}":             "comma,,,dot,.,semi,;",
+		"{+keys*}":            "comma=,,dot=.,semi=;",
+		// Section 3.2.4: Fragment Expansion.
+		"{#var}":         "#value",
+		"{#hello}":       "#Hello%20World!",
+		"{#half}":        "#50%25",
+		"foo{#empty}":    "foo#",
+		"foo{#undef}":    "foo",
+		"{#x,hello,y}":   "#1024,Hello%20World!,768",
+		"{#path,x}/here": "#/foo/bar,1024/here",
+		"{#path:6}/here": "#/foo/b/here",
+		"{#list}":        "#red,green,blue",
+		"{#list*}":       "#red,green,blue",
+		"{#keys}":        "#comma,,,dot,.,semi,;",
+		"{#keys*}":       "#comma=,,dot=.,semi=;",
+		// Section 3.2.5: Label Expansion with Dot-Prefix.
+		"{.who}":          ".fred",
+		"{.who,who}":      ".fred.fred",
+		"{.half,who}":     ".50%25.fred",
+		"www{.dom*}":      "www.example.com",
+		"X{.var}":         "X.value",
+		"X{.empty}":       "X.",
+		"X{.undef}":       "X",
+		"X{.var:3}":       "X.val",
+		"X{.list}":        "X.red,green,blue",
+		"X{.list*}":       "X.red.green.blue",
+		"X{.keys}":        "X.comma,%2C,dot,.,semi,%3B",
+		"X{.keys*}":       "X.comma=%2C.dot=..semi=%3B",
+		"X{.empty_keys}":  "X",
+		"X{.empty_keys*}": "X",
+		// Section 3.2.6: Path Segment Expansion.
+		"{/who}":          "/fred",
+		"{/who,who}":      "/fred/fred",
+		"{/half,who}":     "/50%25/fred",
+		"{/who,dub}":      "/fred/me%2Ftoo",
+		"{/var}":          "/value",
+		"{/var,empty}":    "/value/",
+		"{/var,undef}":    "/value",
+		"{/var,x}/here":   "/value/1024/here",
+		"{/var:1,var}":    "/v/value",
+		"{/list}":         "/red,green,blue",
+		"{/list*}":        "/red/green/blue",
+		"{/list*,path:4}": "/red/green/blue/%2Ffoo",
+		"{/keys}":         "/comma,%2C,dot,.,semi,%3B",
+		"{/keys*}":        "/comma=%2C/dot=./semi=%3B",
+		// Section 3.2.7: Path-Style Parameter Expansion.
+		"{;who}":         ";who=fred",
+		"{;half}":        ";half=50%25",
+		"{;empty}":       ";empty",
+		"{;v,empty,who}": ";v=6;empty;who=fred",
+		"{;v,bar,who}":   ";v=6;who=fred",
+		"{;x,y}":         ";x=1024;y=768",
+		"{;x,y,empty}":   ";x=1024;y=768;empty",
+		"{;x,y,undef}":   ";x=1024;y=768",
+		"{;hello:5}":     ";hello=Hello",
+		"{;list}":        ";list=red,green,blue",
+		"{;list*}":       ";list=red;list=green;list=blue",
+		"{;keys}":        ";keys=comma,%2C,dot,.,semi,%3B",
+		"{;keys*}":       ";comma=%2C;dot=.;semi=%3B",
+		// Section 3.2.8: Form-Style Query Expansion.
+		"{?who}":       "?who=fred",
+		"{?half}":      "?half=50%25",
+		"{?x,y}":       "?x=1024&y=768",
+		"{?x,y,empty}": "?x=1024&y=768&empty=",
+		"{?x,y,undef}": "?x=1024&y=768",
+		"{?var:3}":     "?var=val",
+		"{?list}":      "?list=red,green,blue",
+		"{?list*}":     "?list=red&list=green&list=blue",
+		"{?keys}":      "?keys=comma,%2C,dot,.,semi,%3B",
+		"{?keys*}":     "?comma=%2C&dot=.&semi=%3B",
+		// Section 3.2.9: Form-Style Query Continuation.
+		"{&who}":         "&who=fred",
+		"{&half}":        "&half=50%25",
+		"?fixed=yes{&x}": "?fixed=yes&x=1024",
+		"{&x,y,empty}":   "&x=1024&y=768&empty=",
+		"{&var:3}":       "&var=val",
+		"{&list}":        "&list=red,green,blue",
+		"{&list*}":       "&list=red&list=green&list=blue",
+		"{&keys}":        "&keys=comma,%2C,dot,.,semi,%3B",
+		"{&keys*}":       "&comma=%2C&dot=.&semi=%3B",
+	}
+
+	vars := rfcVariables()
+	for template, expected := range testCases {
+		t.Run(template, func(t *testing.T) {
+			tmpl, err := Parse(template)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned an unexpected error: %v", template, err)
+			}
+			ref, err := tmpl.Expand(vars)
+			if err != nil {
+				t.Fatalf("Expand() returned an unexpected error: %v", err)
+			}
+			if ref.String() != expected {
+				t.Errorf("Expand() = %q, want %q", ref.String(), expected)
+			}
+		})
+	}
+}
+
+// TestTemplate_Expand tests expansions beyond the RFC examples.
+func TestTemplate_Expand(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		vars     map[string]any
+		expected string
+	}{
+		{
+			name:     "URL with path and query",
+			template: "http://a/{id}{?q,page}",
+			vars:     map[string]any{"id": 42, "q": "go lang", "page": 2},
+			expected: "http://a/42?q=go%20lang&page=2",
+		},
+		{
+			name:     "Exploded map with an empty value, unnamed operator",
+			template: "{/keys*}",
+			vars:     map[string]any{"keys": map[string]string{"a": ""}},
+			expected: "/a=",
+		},
+		{
+			name:     "Exploded map with an empty value, each operator",
+			template: "{keys*}{+keys*}{#keys*}{.keys*}{;keys*}{?keys*}{&keys*}",
+			vars:     map[string]any{"keys": map[string]string{"a": "", "b": "1"}},
+			expected: "a=,b=1a=,b=1#a=,b=1.a=.b=1;a;b=1?a=&b=1&a=&b=1",
+		},
+		{
+			name:     "Non-ASCII value",
+			template: "/{name}",
+			vars:     map[string]any{"name": "résumé"},
+			expected: "/r%C3%A9sum%C3%A9",
+		},
+		{
+			name:     "Prefix counts characters, not bytes",
+			template: "/{name:2}",
+			vars:     map[string]any{"name": "résumé"},
+			expected: "/r%C3%A9",
+		},
+		{
+			name:     "Non-ASCII and encoded literals",
+			template: "/é%20{var}",
+			vars:     map[string]any{"var": "x"},
+			expected: "/%C3%A9%20x",
+		},
+		{
+			name:     "Scalar types",
+			template: "{?b,f,l}",
+			vars:     map[string]any{"b": true, "f": 1.5, "l": []any{1, "two", false}},
+			expected: "?b=true&f=1.5&l=1,two,false",
+		},
+		{
+			name:     "Map of any",
+			template: "{?m*}",
+			vars:     map[string]any{"m": map[string]any{"b": 2, "a": ""}},
+			expected: "?a=&b=2",
+		},
+		{
+			name:     "Variable name with dot and encoding",
+			template: "{a.b,c%20d}",
+			vars:     map[string]any{"a.b": "1", "c%20d": "2"},
+			expected: "1,2",
+		},
+		{
+			name:     "No variables",
+			template: "http://example.com/",
+			vars:     nil,
+			expected: "http://example.com/",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := Parse(tc.template)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned an unexpected error: %v", tc.template, err)
+			}
+			if tmpl.String() != tc.template {
+				t.Errorf("String() = %q, want %q", tmpl.String(), tc.template)
+			}
+			ref, err := tmpl.Expand(tc.vars)
+			if err != nil {
+				t.Fatalf("Expand() returned an unexpected error: %v", err)
+			}
+			if ref.String() != tc.expected {
+				t.Errorf("Expand() = %q, want %q", ref.String(), tc.expected)
+			}
+		})
+	}
+}
+
+// TestParse_Invalid tests that malformed templates are rejected.
+func TestParse_Invalid(t *testing.T) {
+	testCases := []struct {
+		template string
+		err      error
+	}{
+		{"{var", ErrUnclosedExpression},
+		{"a{b}{c", ErrUnclosedExpression},
+		{"{}", ErrInvalidExpression},
+		{"{+}", ErrInvalidExpression},
+		{"{=var}", ErrInvalidExpression},
+		{"{var,}", ErrInvalidExpression},
+		{"{a..b}", ErrInvalidExpression},
+		{"{.a.}", ErrInvalidExpression},
+		{"{a-b}", ErrInvalidExpression},
+		{"{a%2}", ErrInvalidExpression},
+		{"{var:0}", ErrInvalidExpression},
+		{"{var:01}", ErrInvalidExpression},
+		{"{var:10000}", ErrInvalidExpression},
+		{"{var:x}", ErrInvalidExpression},
+		{"{var:3*}", ErrInvalidExpression},
+		{"a b", ErrInvalidLiteral},
+		{"a}b", ErrInvalidLiteral},
+		{"100%", ErrInvalidLiteral},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.template, func(t *testing.T) {
+			if _, err := Parse(tc.template); !errors.Is(err, tc.err) {
+				t.Errorf("Parse(%q) error = %v, want %v", tc.template, err, tc.err)
+			}
+		})
+	}
+}
+
+// TestTemplate_Expand_Invalid tests the errors reported during expansion.
+func TestTemplate_Expand_Invalid(t *testing.T) {
+	t.Run("Prefix on a list", func(t *testing.T) {
+		tmpl, _ := Parse("{list:3}")
+		_, err := tmpl.Expand(map[string]any{"list": []string{"a"}})
+		if !errors.Is(err, ErrPrefixOnComposite) {
+			t.Errorf("Expand() error = %v, want ErrPrefixOnComposite", err)
+		}
+	})
+
+	t.Run("Unsupported value", func(t *testing.T) {
+		tmpl, _ := Parse("{var}")
+		_, err := tmpl.Expand(map[string]any{"var": struct{}{}})
+		if !errors.Is(err, ErrUnsupportedValue) {
+			t.Errorf("Expand() error = %v, want ErrUnsupportedValue", err)
+		}
+	})
+
+	t.Run("Unsupported list item", func(t *testing.T) {
+		tmpl, _ := Parse("{var}")
+		_, err := tmpl.Expand(map[string]any{"var": []any{[]int{1}}})
+		if !errors.Is(err, ErrUnsupportedValue) {
+			t.Errorf("Expand() error = %v, want ErrUnsupportedValue", err)
+		}
+	})
+
+	t.Run("Invalid IRI reference", func(t *testing.T) {
+		tmpl, _ := Parse("{+var}")
+		_, err := tmpl.Expand(map[string]any{"var": ":a"})
+		var parseErr *iri.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("Expand() error = %v, want an *iri.ParseError", err)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 Trident Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uritemplate
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// value is a template variable converted to one of the three kinds of values
+// of RFC 6570, Section 2.3: a string, a list, or an associative array.
+type value struct {
+	defined bool
+	str     string
+	list    []string
+	keys    []string
+	values  []string
+}
+
+// isUndefined reports whether the variable must be skipped by the expansion.
+func (v value) isUndefined() bool {
+	return !v.defined
+}
+
+// lookup converts the value bound to name in vars. A missing or nil value, or
+// an empty list or map, is undefined.
+func lookup(vars map[string]any, name string) (value, error) {
+	raw, ok := vars[name]
+	if !ok || raw == nil {
+		return value{}, nil
+	}
+
+	switch val := raw.(type) {
+	case []string:
+		if len(val) == 0 {
+			return value{}, nil
+		}
+		return value{defined: true, list: val}, nil
+	case []any:
+		if len(val) == 0 {
+			return value{}, nil
+		}
+		list := make([]string, len(val))
+		for i, item := range val {
+			s, err := scalar(name, item)
+			if err != nil {
+				return value{}, err
+			}
+			list[i] = s
+		}
+		return value{defined: true, list: list}, nil
+	case map[string]string:
+		if len(val) == 0 {
+			return value{}, nil
+		}
+		keys := sortedKeys(val)
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			values[i] = val[key]
+		}
+		return value{defined: true, keys: keys, values: values}, nil
+	case map[string]any:
+		if len(val) == 0 {
+			return value{}, nil
+		}
+		keys := sortedKeys(val)
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			s, err := scalar(name, val[key])
+			if err != nil {
+				return value{}, err
+			}
+			values[i] = s
+		}
+		return value{defined: true, keys: keys, values: values}, nil
+	default:
+		s, err := scalar(name, raw)
+		if err != nil {
+			return value{}, err
+		}
+		return value{defined: true, str: s}, nil
+	}
+}
+
+// scalar converts a string-like value to its string form.
+func scalar(name string, raw any) (string, error) {
+	switch val := raw.(type) {
+	case string:
+		return val, nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(val), nil
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64), nil
+	case fmt.Stringer:
+		return val.String(), nil
+	default:
+		return "", fmt.Errorf("%w: %s has type %T", ErrUnsupportedValue, name, raw)
+	}
+}
+
+// sortedKeys returns the keys of a map in lexicographic order.
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}